@@ -1,16 +1,21 @@
 package core
 
 import (
-	"crypto/ecdsa"
+	"bytes"
 	"encoding/gob"
 	"encoding/json"
 	"errors"
 	"io"
 
+	"github.com/kookehs/watchmen/crypto"
 	"github.com/kookehs/watchmen/primitives"
 )
 
+// ErrNoPrivateKey is returned when an Account without a private key is asked to sign.
+var ErrNoPrivateKey = errors.New("Account does not have a private key")
+
 // Account contains address as well as the key that generated it.
+// Key is nil for accounts imported without their private keys.
 type Account struct {
 	BBAN      primitives.BBAN `json:"bban"`
 	Delegate  bool            `json:"delegate"`
@@ -19,7 +24,11 @@ type Account struct {
 	IBAN      primitives.IBAN `json:"iban"`
 	Key       *primitives.Key `json:"key"`
 	Missed    uint64          `json:"missed"`
-	Share     float64         `json:"share"`
+	// Multi-signature policy controlling the account in place of Key
+	Policy *primitives.Policy `json:"policy,omitempty"`
+	Share  float64            `json:"share"`
+	// Key authorized by the latest OpenBlock or RotateBlock appended to the chain
+	authorized crypto.Verifier
 }
 
 // NewAccount creates and initializes an account with the given key.
@@ -35,12 +44,32 @@ func NewAccount(key *primitives.Key) *Account {
 		IBAN:      iban,
 		Key:       key,
 		Missed:    0,
+		Policy:    nil,
+		Share:     0,
+	}
+}
+
+// NewMultisigAccount creates and initializes an account controlled by the given Policy.
+func NewMultisigAccount(policy *primitives.Policy) *Account {
+	address := policy.Address()
+	bban := primitives.MakeBBAN([]byte(address.String()))
+	iban := primitives.MakeIBAN([]byte("TV00" + bban.String()))
+
+	return &Account{
+		BBAN:      bban,
+		Delegate:  false,
+		Delegates: make(map[IBAN]bool),
+		Forged:    0,
+		IBAN:      iban,
+		Key:       nil,
+		Missed:    0,
+		Policy:    policy,
 		Share:     0,
 	}
 }
 
 // VerifyBlock returns whether or not the block was signed by the given key.
-func VerifyBlock(block primitives.Block, key *ecdsa.PublicKey) error {
+func VerifyBlock(block primitives.Block, key crypto.Verifier) error {
 	verified, err := block.Verify(key)
 
 	if err != nil {
@@ -54,22 +83,82 @@ func VerifyBlock(block primitives.Block, key *ecdsa.PublicKey) error {
 	return nil
 }
 
+// VerifyPolicyBlock returns whether or not the block was signed by enough keys of the given Policy.
+func VerifyPolicyBlock(block primitives.Block, policy *primitives.Policy) error {
+	verified, err := block.VerifyPolicy(policy)
+
+	if err != nil {
+		return err
+	}
+
+	if !verified {
+		return errors.New("Block was not signed by enough keys of the policy")
+	}
+
+	return nil
+}
+
+// Multisig returns whether the Account is controlled by a multi-signature Policy.
+func (a *Account) Multisig() bool {
+	return a.Policy != nil
+}
+
+// Signer returns the private key of the Account.
+func (a *Account) Signer() (crypto.Signer, error) {
+	if a.Key == nil {
+		return nil, ErrNoPrivateKey
+	}
+
+	return a.Key.Signer(), nil
+}
+
+// AuthorizedKey returns the key authorized to sign blocks by the chain of the Account.
+// Key only holds the private key used for signing and is not trusted for verification.
+func (a *Account) AuthorizedKey() (crypto.Verifier, error) {
+	if a.authorized == nil {
+		return nil, errors.New("Account does not have an authorized key")
+	}
+
+	return a.authorized, nil
+}
+
+// Verify returns whether or not the block was signed by the owner of the Account.
+// Blocks are verified against the policy of multisig accounts and the authorized key otherwise.
+func (a *Account) Verify(block primitives.Block) error {
+	if a.Multisig() {
+		return VerifyPolicyBlock(block, a.Policy)
+	}
+
+	key, err := a.AuthorizedKey()
+
+	if err != nil {
+		return err
+	}
+
+	return VerifyBlock(block, key)
+}
+
 // CreateChangeBlock creates a blueprint for a ChangeBlock with the given arguments.
-func (a *Account) CreateChangeBlock(delegates []primitives.IBAN, prev primitives.Block) (*Blueprint, error) {
-	if err := VerifyBlock(prev, &a.Key.PrivateKey.PublicKey); err != nil {
+// The given transaction fee and VotingFee are deducted from the balance.
+func (a *Account) CreateChangeBlock(delegates []primitives.IBAN, fee primitives.Amount, prev primitives.Block) (*Blueprint, error) {
+	if err := a.Verify(prev); err != nil {
 		return nil, err
 	}
 
 	cost := primitives.NewAmount(0)
-	cost.Add(TransactionFee, VotingFee)
+	cost.Add(fee, VotingFee)
 
 	if prev.Balance().Cmp(cost) == -1 {
 		return nil, errors.New("Insufficient funds")
 	}
 
+	balance := primitives.NewAmount(0)
+	balance.Sub(prev.Balance(), cost)
+
 	blueprint := &Blueprint{
-		Balance:   prev.Balance(),
+		Balance:   balance,
 		Delegates: delegates,
+		Fee:       cost,
 		Previous:  prev,
 		Type:      primitives.Change,
 	}
@@ -78,8 +167,9 @@ func (a *Account) CreateChangeBlock(delegates []primitives.IBAN, prev primitives
 }
 
 // CreateDelegateBlock creates a blueprint for a DelegateBlock with the given arguments.
-func (a *Account) CreateDelegateBlock(prev primitives.Block, share float64) (*Blueprint, error) {
-	if err := VerifyBlock(prev, &a.Key.PrivateKey.PublicKey); err != nil {
+// The given transaction fee and DelegateFee are deducted from the balance.
+func (a *Account) CreateDelegateBlock(fee primitives.Amount, prev primitives.Block, share float64) (*Blueprint, error) {
+	if err := a.Verify(prev); err != nil {
 		return nil, err
 	}
 
@@ -88,7 +178,7 @@ func (a *Account) CreateDelegateBlock(prev primitives.Block, share float64) (*Bl
 	}
 
 	cost := primitives.NewAmount(0)
-	cost.Add(TransactionFee, DelegateFee)
+	cost.Add(fee, DelegateFee)
 
 	if prev.Balance().Cmp(cost) == -1 {
 		return nil, errors.New("Insufficient funds")
@@ -98,8 +188,17 @@ func (a *Account) CreateDelegateBlock(prev primitives.Block, share float64) (*Bl
 		return nil, errors.New("Account is already a delegate")
 	}
 
+	// Forging requires a single key to witness blocks.
+	if a.Multisig() {
+		return nil, errors.New("Multisig accounts cannot become delegates")
+	}
+
+	balance := primitives.NewAmount(0)
+	balance.Sub(prev.Balance(), cost)
+
 	blueprint := &Blueprint{
-		Balance:  prev.Balance(),
+		Balance:  balance,
+		Fee:      cost,
 		Previous: prev,
 		Share:    share,
 		Type:     primitives.Delegate,
@@ -108,6 +207,32 @@ func (a *Account) CreateDelegateBlock(prev primitives.Block, share float64) (*Bl
 	return blueprint, nil
 }
 
+// CreateNameBlock creates a blueprint for a NameBlock with the given arguments.
+func (a *Account) CreateNameBlock(action primitives.NameAction, name string, dst primitives.IBAN, fee primitives.Amount, prev primitives.Block) (*Blueprint, error) {
+	if err := a.Verify(prev); err != nil {
+		return nil, err
+	}
+
+	if prev.Balance().Cmp(fee) == -1 {
+		return nil, errors.New("Insufficient funds")
+	}
+
+	balance := primitives.NewAmount(0)
+	balance.Sub(prev.Balance(), fee)
+
+	blueprint := &Blueprint{
+		Action:      action,
+		Balance:     balance,
+		Destination: dst,
+		Fee:         fee,
+		Name:        name,
+		Previous:    prev,
+		Type:        primitives.Name,
+	}
+
+	return blueprint, nil
+}
+
 // CreateOpenBlock creates a blueprint for an OpenBlock.
 func (a *Account) CreateOpenBlock(amt primitives.Amount) (*Blueprint, error) {
 	blueprint := &Blueprint{
@@ -119,13 +244,14 @@ func (a *Account) CreateOpenBlock(amt primitives.Amount) (*Blueprint, error) {
 }
 
 // CreateReceiveBlock creates a blueprint for a ReceiveBlock with the given arguments.
-func (a *Account) CreateReceiveBlock(amt primitives.Amount, key *ecdsa.PublicKey, prev, src primitives.Block) (*Blueprint, error) {
-	if err := VerifyBlock(prev, &a.Key.PrivateKey.PublicKey); err != nil {
+// The source block is verified against the sender when one is given.
+func (a *Account) CreateReceiveBlock(amt primitives.Amount, sender *Account, prev, src primitives.Block) (*Blueprint, error) {
+	if err := a.Verify(prev); err != nil {
 		return nil, err
 	}
 
-	if key != nil {
-		if err := VerifyBlock(src, key); err != nil {
+	if sender != nil {
+		if err := sender.Verify(src); err != nil {
 			return nil, err
 		}
 	}
@@ -144,26 +270,84 @@ func (a *Account) CreateReceiveBlock(amt primitives.Amount, key *ecdsa.PublicKey
 	return blueprint, nil
 }
 
+// CreateRotateBlock creates a blueprint for a RotateBlock authorizing the given key.
+// The current key signs the authorization while the new key signs the block itself.
+// Rotating keys is free so that a compromised key can always be replaced.
+func (a *Account) CreateRotateBlock(key *primitives.Key, prev primitives.Block) (*Blueprint, error) {
+	if err := a.Verify(prev); err != nil {
+		return nil, err
+	}
+
+	if a.Multisig() {
+		return nil, errors.New("Multisig accounts cannot rotate keys")
+	}
+
+	authorized, err := a.AuthorizedKey()
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := a.Signer()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(authorized.Bytes(), signer.Verifier().Bytes()) {
+		return nil, errors.New("Account key is not the key authorized by the chain")
+	}
+
+	if (key == nil) || bytes.Equal(key.Verifier().Bytes(), authorized.Bytes()) {
+		return nil, errors.New("Invalid key to rotate to")
+	}
+
+	hash, err := prev.Hash()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rotation := primitives.RotationHash(primitives.MakePublicKey(key.Verifier()), hash)
+	authorization, err := primitives.SignHash(rotation, signer)
+
+	if err != nil {
+		return nil, err
+	}
+
+	blueprint := &Blueprint{
+		Authorization: authorization,
+		Balance:       prev.Balance(),
+		Key:           key,
+		Previous:      prev,
+		Type:          primitives.Rotate,
+	}
+
+	return blueprint, nil
+}
+
 // CreateSendBlock creates a blueprint for a SendBlock with the given arguments.
-func (a *Account) CreateSendBlock(amt primitives.Amount, dst primitives.IBAN, prev primitives.Block) (*Blueprint, error) {
-	if err := VerifyBlock(prev, &a.Key.PrivateKey.PublicKey); err != nil {
+// The given transaction fee is deducted from the balance along with the amount.
+func (a *Account) CreateSendBlock(amt, fee primitives.Amount, dst primitives.IBAN, prev primitives.Block) (*Blueprint, error) {
+	if err := a.Verify(prev); err != nil {
 		return nil, err
 	}
 
 	cost := primitives.NewAmount(0)
-	cost.Add(TransactionFee, amt)
+	cost.Add(fee, amt)
 
 	if prev.Balance().Cmp(cost) == -1 {
 		return nil, errors.New("Insufficient funds")
 	}
 
 	balance := primitives.NewAmount(0)
-	balance.Sub(prev.Balance(), amt)
+	balance.Sub(prev.Balance(), cost)
 
 	blueprint := &Blueprint{
 		Amount:      amt,
 		Balance:     balance,
 		Destination: dst,
+		Fee:         fee,
 		Previous:    prev,
 		Type:        primitives.Send,
 	}
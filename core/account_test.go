@@ -0,0 +1,175 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/kookehs/watchmen/crypto"
+	"github.com/kookehs/watchmen/primitives"
+)
+
+func mustKey(t *testing.T, scheme crypto.Scheme) *primitives.Key {
+	t.Helper()
+	key, err := primitives.NewSchemeKeyForICAP(scheme, rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+// checkAuthorizedKey reports whether the key authorized by the chain of the given IBAN differs from key.
+func checkAuthorizedKey(t *testing.T, ledger *Ledger, iban primitives.IBAN, key *primitives.Key) {
+	t.Helper()
+	authorized, err := ledger.AuthorizedKey(iban)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(authorized.Bytes(), key.Verifier().Bytes()) {
+		t.Errorf("Authorized key of %v = %x, want %x", iban.String(), authorized.Bytes(), key.Verifier().Bytes())
+	}
+}
+
+func TestRotateKey(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, node, alice, bob := test.ledger, test.node, test.alice, test.bob
+	old := bob.Key
+	key := mustKey(t, crypto.P256)
+
+	if _, err := node.RotateKey(bob, key); err != nil {
+		t.Fatal(err)
+	}
+
+	if bob.Key != key {
+		t.Error("Account key was not replaced by the rotated key")
+	}
+
+	checkAuthorizedKey(t, ledger, bob.IBAN, key)
+
+	// The IBAN remains derived from the key the account was opened with.
+	if !ledger.VerifyIBAN(bob.IBAN) {
+		t.Error("IBAN no longer verifies after rotation")
+	}
+
+	// Blocks signed by the old key are rejected.
+	bob.Key = old
+	prev := ledger.LatestBlock(bob.IBAN)
+
+	if _, err := node.Transfer(primitives.NewAmount(1), alice.IBAN, bob.IBAN); err == nil {
+		t.Error("Transfer signed by the old key succeeded")
+	}
+
+	if _, err := node.RotateKey(bob, mustKey(t, crypto.Ed25519)); err == nil {
+		t.Error("RotateKey authorized by the old key succeeded")
+	}
+
+	if ledger.LatestBlock(bob.IBAN) != prev {
+		t.Error("Block signed by the old key was appended")
+	}
+
+	bob.Key = key
+	mustTransfer(t, node, 1, alice, bob)
+
+	if _, err := node.RotateKey(bob, key); err == nil {
+		t.Error("RotateKey to the authorized key succeeded")
+	}
+
+	if _, err := node.RotateKey(test.vault, key); err == nil {
+		t.Error("RotateKey of multisig account succeeded")
+	}
+}
+
+func TestRotateBlockAuthorization(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, bob := test.ledger, test.bob
+	prev := ledger.LatestBlock(bob.IBAN)
+	hash, err := prev.Hash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	attacker := mustKey(t, crypto.Ed25519)
+	pub := primitives.MakePublicKey(attacker.Verifier())
+	other := primitives.MakePublicKey(mustKey(t, crypto.Ed25519).Verifier())
+
+	// Authorizations must be signed by the current key for the key being rotated to and the previous block.
+	forged := []struct {
+		name   string
+		signer crypto.Signer
+		hash   []byte
+	}{
+		{"signed by the new key", attacker.Signer(), primitives.RotationHash(pub, hash)},
+		{"for another key", bob.Key.Signer(), primitives.RotationHash(other, hash)},
+		{"for another block", bob.Key.Signer(), primitives.RotationHash(pub, primitives.BlockHash{1})},
+	}
+
+	for _, tc := range forged {
+		authorization, err := primitives.SignHash(tc.hash, tc.signer)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rotate := primitives.NewRotateBlock(prev.Balance(), pub, authorization, hash)
+
+		if err := rotate.Sign(attacker.Signer()); err != nil {
+			t.Fatal(err)
+		}
+
+		if rotate.VerifyAuthorization(bob.Key.Verifier()) {
+			t.Errorf("VerifyAuthorization of authorization %v succeeded", tc.name)
+		}
+
+		if err := ledger.AppendBlock(rotate, bob.IBAN); err == nil {
+			t.Errorf("AppendBlock of RotateBlock with authorization %v succeeded", tc.name)
+		}
+	}
+
+	checkAuthorizedKey(t, ledger, bob.IBAN, bob.Key)
+}
+
+func TestAuthorizedKeyAfterImport(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, node, alice := test.ledger, test.node, test.alice
+
+	// Rotate a second time so the chain holds more than one rotation.
+	key := mustKey(t, crypto.P256)
+
+	if _, err := node.RotateKey(alice, key); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(ledger)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported := &Ledger{}
+
+	if err := json.Unmarshal(data, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	checkAuthorizedKey(t, imported, alice.IBAN, key)
+
+	// The chain is replayed when the authorized key has not been cached.
+	imported.Accounts[alice.IBAN.String()].authorized = nil
+	checkAuthorizedKey(t, imported, alice.IBAN, key)
+
+	open, err := imported.OpenKey(alice.IBAN)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Equal(open.Bytes(), key.Verifier().Bytes()) {
+		t.Error("OpenKey returned the rotated key")
+	}
+}
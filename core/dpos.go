@@ -7,6 +7,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/kookehs/watchmen/crypto"
 	"github.com/kookehs/watchmen/primitives"
 )
 
@@ -22,20 +23,28 @@ var (
 	MaxForgers             int = 101
 
 	// Rewards
-	// TODO: Consider decreasing reward amount.
-	ForgeReward primitives.Amount = primitives.NewAmount(4)
+	ForgeReward    primitives.Amount = primitives.NewAmount(4)
+	MinForgeReward primitives.Amount = primitives.NewAmount(0.5)
+	// Fraction by which ForgeReward declines every RewardDecayRounds rounds
+	RewardDecay       float64 = 0.1
+	RewardDecayRounds uint64  = 1000
 )
 
 // Blueprint contains information used to create a block.
 type Blueprint struct {
-	Amount      primitives.Amount
-	Balance     primitives.Amount
-	Delegates   []primitives.IBAN
-	Destination primitives.IBAN
-	Previous    primitives.Block
-	Share       float64
-	Source      primitives.Block
-	Type        primitives.BlockType
+	Action        primitives.NameAction
+	Amount        primitives.Amount
+	Authorization primitives.Signature
+	Balance       primitives.Amount
+	Delegates     []primitives.IBAN
+	Destination   primitives.IBAN
+	Fee           primitives.Amount
+	Key           *primitives.Key
+	Name          string
+	Previous      primitives.Block
+	Share         float64
+	Source        primitives.Block
+	Type          primitives.BlockType
 }
 
 // Delegate contains an Account and their total weight.
@@ -75,17 +84,55 @@ func (d Delegates) Less(i, j int) bool {
 type DPoS struct {
 	// All delegates with their respective total weight
 	Delegates Delegates
-	Round     *Round
+	// Rewards accrued during the current round
+	Rewards map[IBAN]primitives.Amount
+	Round   *Round
+	// Number of rounds that have been started
+	Rounds uint64
+	// Rewards from finished rounds waiting to be paid out
+	Settlements map[IBAN]primitives.Amount
 }
 
 // NewDPoS returns a pointer to an initialized DPoS.
 func NewDPoS() *DPoS {
 	return &DPoS{
-		Delegates: make(Delegates, 0),
-		Round:     NewRound(Delegates{}),
+		Delegates:   make(Delegates, 0),
+		Rewards:     make(map[IBAN]primitives.Amount),
+		Round:       NewRound(Delegates{}),
+		Rounds:      0,
+		Settlements: make(map[IBAN]primitives.Amount),
 	}
 }
 
+// Accrue adds the given amount to the rewards of the given IBAN for the current round.
+func (d *DPoS) Accrue(iban primitives.IBAN, amt primitives.Amount) {
+	AddAmount(d.Rewards, iban.String(), amt)
+}
+
+// Accrued returns the rewards of the given IBAN that have not been paid out yet.
+func (d *DPoS) Accrued(iban primitives.IBAN) primitives.Amount {
+	accrued := primitives.NewAmount(0)
+
+	if reward, exist := d.Rewards[iban.String()]; exist {
+		accrued.Add(accrued, reward)
+	}
+
+	if settlement, exist := d.Settlements[iban.String()]; exist {
+		accrued.Add(accrued, settlement)
+	}
+
+	return accrued
+}
+
+// AddAmount adds the given amount to the amount stored under the given IBAN.
+func AddAmount(amounts map[IBAN]primitives.Amount, iban IBAN, amt primitives.Amount) {
+	if _, exist := amounts[iban]; !exist {
+		amounts[iban] = primitives.NewAmount(0)
+	}
+
+	amounts[iban].Add(amounts[iban], amt)
+}
+
 // CalculateWeights iterates through all accounts and their delegates.
 // The final weight is the sum of the amounts each supporter holds.
 func CalculateWeights(ledger *Ledger) Delegates {
@@ -146,15 +193,16 @@ func CheckMaxDelegateLimit(account *Account, delegates []string) error {
 	return nil
 }
 
-// Elect processes the given delegates and distribute the fee to newly elected delegates.
-func (d *DPoS) Elect(account *Account, delegates []string, ledger *Ledger, node *Node) error {
+// elect processes the given delegates and distribute the fee to newly elected delegates.
+// Signers are only required for multisig accounts.
+func (d *DPoS) elect(account *Account, delegates []string, ledger *Ledger, node *Node, signers []crypto.Signer) error {
 	err := CheckMaxDelegateLimit(account, delegates)
 
 	if err != nil {
 		return err
 	}
 
-	_, err = d.ParseDelegates(account, delegates, ledger, node)
+	_, err = d.parseDelegates(account, delegates, ledger, node, signers)
 
 	if err != nil {
 		return err
@@ -167,14 +215,14 @@ func (d *DPoS) Elect(account *Account, delegates []string, ledger *Ledger, node
 func ParseDelegateString(delegate string, ledger *Ledger) (byte, *Account) {
 	symbol := byte(delegate[0])
 	username := strings.ToLower(delegate[1:])
-	iban := ledger.Users[username]
+	iban, _ := ledger.Users.IBAN(username)
 	account := ledger.Accounts[iban.String()]
 	return symbol, account
 }
 
-// ParseDelegates updates the delegates for the given Account.
+// parseDelegates updates the delegates for the given Account.
 // It may create multiple ChangeBlocks depending on the number of delegates.
-func (d *DPoS) ParseDelegates(account *Account, delegates []string, ledger *Ledger, node *Node) ([]*Account, error) {
+func (d *DPoS) parseDelegates(account *Account, delegates []string, ledger *Ledger, node *Node, signers []crypto.Signer) ([]*Account, error) {
 	length := len(delegates)
 
 	if length == 0 {
@@ -188,7 +236,7 @@ func (d *DPoS) ParseDelegates(account *Account, delegates []string, ledger *Ledg
 	}
 
 	accounts := make([]*Account, 0)
-	elected, err := d.ParseDelegates(account, delegates[split:], ledger, node)
+	elected, err := d.parseDelegates(account, delegates[split:], ledger, node, signers)
 
 	if err != nil {
 		return nil, err
@@ -204,6 +252,12 @@ func (d *DPoS) ParseDelegates(account *Account, delegates []string, ledger *Ledg
 		}
 
 		symbol, delegate := ParseDelegateString(change, ledger)
+
+		if delegate == nil {
+			log.Printf("Unknown delegate %v\n", change[1:])
+			continue
+		}
+
 		iban := delegate.IBAN
 		_, exist := account.Delegates[iban.String()]
 
@@ -229,26 +283,62 @@ func (d *DPoS) ParseDelegates(account *Account, delegates []string, ledger *Ledg
 	}
 
 	prev := ledger.LatestBlock(account.IBAN)
-	blueprint, err := account.CreateChangeBlock(ibans, prev)
+	blueprint, err := account.CreateChangeBlock(ibans, node.Fee(), prev)
 
 	if err != nil {
 		return nil, err
 	}
 
-	request := NewRequest(account, blueprint)
+	request := NewMultisigRequest(account, blueprint, signers)
 
-	if _, err := node.Process(request); err != nil {
+	if _, err := node.process(request); err != nil {
 		return nil, err
 	}
 
 	return accounts, nil
 }
 
+// Reward returns the amount minted for forging a block during the current round.
+// ForgeReward declines by RewardDecay every RewardDecayRounds rounds down to MinForgeReward.
+func (d *DPoS) Reward() primitives.Amount {
+	reward := primitives.NewAmount(0)
+	reward.Copy(ForgeReward)
+
+	if RewardDecayRounds == 0 {
+		return reward
+	}
+
+	decay := primitives.NewAmount(1 - RewardDecay)
+
+	for i := uint64(0); i < d.Rounds/RewardDecayRounds; i++ {
+		reward.Mul(reward, decay)
+
+		if reward.Cmp(MinForgeReward) != 1 {
+			reward.Copy(MinForgeReward)
+			break
+		}
+	}
+
+	return reward
+}
+
+// Settle moves the rewards accrued during the current round to the settlements awaiting payout.
+func (d *DPoS) Settle() {
+	for iban, reward := range d.Rewards {
+		AddAmount(d.Settlements, iban, reward)
+	}
+
+	d.Rewards = make(map[IBAN]primitives.Amount)
+}
+
 // Update checks if a new round needs to be created and returns the current forger.
+// Rewards accrued during the finished round are settled on rollover.
 func (d *DPoS) Update(ledger *Ledger) Delegates {
-	if (len(d.Round.Forgers) == 0) || ((d.Round.Index != 0) && ((d.Round.Index % len(d.Round.Forgers)) == 0)) {
+	if d.Round.Ended() {
+		d.Settle()
 		d.Delegates = CalculateWeights(ledger)
 		d.Round = NewRound(d.Delegates)
+		d.Rounds++
 	}
 
 	return d.Round.Forgers
@@ -276,6 +366,14 @@ func NewRound(delegates Delegates) *Round {
 
 // Forge will have the Delegate at Index create the next block.
 func (r *Round) Forge(account *Account, blueprint *Blueprint) (primitives.Block, error) {
+	forger := r.Forgers[r.Index]
+	r.Index++
+	return forger.Forge(account, blueprint)
+}
+
+// Forge creates the block described by the given blueprint witnessed by the Delegate.
+// The Round is not advanced so the block does not take a forging slot.
+func (d *Delegate) Forge(account *Account, blueprint *Blueprint) (primitives.Block, error) {
 	var block, prev primitives.Block
 	var hash primitives.BlockHash
 	var err error
@@ -290,16 +388,26 @@ func (r *Round) Forge(account *Account, blueprint *Blueprint) (primitives.Block,
 		}
 	}
 
-	forger := r.Forgers[r.Index]
-	r.Index++
-
 	switch blueprint.Type {
 	case primitives.Change:
 		block = primitives.NewChangeBlock(blueprint.Balance, blueprint.Delegates, hash)
 	case primitives.Delegate:
 		block = primitives.NewDelegateBlock(blueprint.Balance, hash, blueprint.Share)
+	case primitives.Name:
+		block = primitives.NewNameBlock(blueprint.Balance, blueprint.Action, blueprint.Name, blueprint.Destination, hash)
 	case primitives.Open:
-		block = primitives.NewOpenBlock(blueprint.Balance, account.IBAN)
+		if account.Multisig() {
+			block = primitives.NewMultisigOpenBlock(blueprint.Balance, account.IBAN, account.Policy)
+		} else {
+			signer, err := account.Signer()
+
+			if err != nil {
+				return nil, err
+			}
+
+			key := primitives.MakePublicKey(signer.Verifier())
+			block = primitives.NewOpenBlock(blueprint.Balance, account.IBAN, &key)
+		}
 	case primitives.Receive:
 		srcHash := primitives.BlockHashZero
 
@@ -310,6 +418,9 @@ func (r *Round) Forge(account *Account, blueprint *Blueprint) (primitives.Block,
 		}
 
 		block = primitives.NewReceiveBlock(blueprint.Balance, hash, srcHash)
+	case primitives.Rotate:
+		key := primitives.MakePublicKey(blueprint.Key.Verifier())
+		block = primitives.NewRotateBlock(blueprint.Balance, key, blueprint.Authorization, hash)
 	case primitives.Send:
 		block = primitives.NewSendBlock(blueprint.Balance, blueprint.Destination, hash)
 	default:
@@ -320,16 +431,27 @@ func (r *Round) Forge(account *Account, blueprint *Blueprint) (primitives.Block,
 		return nil, errors.New("Unabled to forge block")
 	}
 
-	if err := block.SignWitness(forger.Account.Key.PrivateKey); err != nil {
+	witness, err := d.Account.Signer()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := block.SignWitness(witness); err != nil {
 		return nil, err
 	}
 
 	return block, nil
 }
 
+// Ended returns whether every forger in the Round has had their turn.
+func (r *Round) Ended() bool {
+	return (len(r.Forgers) == 0) || ((r.Index != 0) && ((r.Index % len(r.Forgers)) == 0))
+}
+
 // Forger returns the current forger.
 func (r *Round) Forger() (*Delegate, error) {
-	if (len(r.Forgers) == 0) || ((r.Index != 0) && ((r.Index % len(r.Forgers)) == 0)) {
+	if r.Ended() {
 		return nil, errors.New("No current forger. Round has ended.")
 	}
 
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/kookehs/watchmen/primitives"
+)
+
+// copyAmounts returns a copy of the given amounts.
+func copyAmounts(amounts map[IBAN]primitives.Amount) map[IBAN]primitives.Amount {
+	copied := make(map[IBAN]primitives.Amount, len(amounts))
+
+	for iban, amount := range amounts {
+		AddAmount(copied, iban, amount)
+	}
+
+	return copied
+}
+
+// paidOut returns the sum of the rewards paid out to the given chain from index start.
+// Rewards are paid out by ReceiveBlocks without a source.
+func paidOut(blocks primitives.Blocks, start int) primitives.Amount {
+	paid := primitives.NewAmount(0)
+
+	for i := start; i < len(blocks); i++ {
+		if (blocks[i].Type() != primitives.Receive) || (blocks[i].Source() != primitives.BlockHashZero) {
+			continue
+		}
+
+		paid.Add(paid, blocks[i].Balance())
+		paid.Sub(paid, blocks[i-1].Balance())
+	}
+
+	return paid
+}
+
+// closeTo returns whether the given amounts are equal up to the rounding of AmountPrecision.
+func closeTo(a, b primitives.Amount) bool {
+	tolerance := primitives.NewAmount(0)
+	tolerance.Abs(b)
+	tolerance.Mul(tolerance, primitives.NewAmount(1e-12))
+
+	if tolerance.Cmp(primitives.NewAmount(1e-9)) == -1 {
+		tolerance.SetFloat64(1e-9)
+	}
+
+	diff := primitives.NewAmount(0)
+	diff.Sub(a, b)
+	return diff.Abs(diff).Cmp(tolerance) == -1
+}
+
+// unpaid returns the rewards that have not been paid out and the amounts waiting for multisig accounts.
+func unpaid(node *Node) primitives.Amount {
+	amount := primitives.NewAmount(0)
+
+	for _, rewards := range []map[IBAN]primitives.Amount{node.DPoS.Rewards, node.DPoS.Settlements} {
+		for _, reward := range rewards {
+			amount.Add(amount, reward)
+		}
+	}
+
+	for _, receivables := range node.Ledger.Pending {
+		for _, receivable := range receivables {
+			amount.Add(amount, receivable.Amount)
+		}
+	}
+
+	return amount
+}
+
+func TestRewardsPaidOutOnRollover(t *testing.T) {
+	defer func(forgers int) {
+		MaxForgers = forgers
+	}(MaxForgers)
+
+	// Short rounds roll over every few blocks.
+	MaxForgers = 3
+	test := newTestLedger(t)
+	ledger, node := test.ledger, test.node
+	dpos := node.DPoS
+	start := dpos.Rounds
+	rollovers := 0
+
+	for dpos.Rounds < start+6 {
+		rounds := dpos.Rounds
+		accrued := copyAmounts(dpos.Rewards)
+		lengths := make(map[IBAN]int)
+
+		for iban := range accrued {
+			lengths[iban] = len(ledger.Blocks[iban])
+		}
+
+		mustTransfer(t, node, 1, test.alice, test.bob)
+
+		if len(dpos.Rewards) == 0 {
+			t.Fatal("No rewards accrued for forging a SendBlock")
+		}
+
+		if dpos.Rounds == rounds {
+			continue
+		}
+
+		rollovers++
+
+		// Rewards of the finished round are paid out once it rolls over.
+		if len(dpos.Settlements) != 0 {
+			t.Errorf("%v settlements of round %v were not paid out", len(dpos.Settlements), rounds)
+		}
+
+		for iban, reward := range accrued {
+			if paid := paidOut(ledger.Blocks[iban], lengths[iban]); !closeTo(paid, reward) {
+				t.Errorf("Paid out %v to %v at the end of round %v, want %v", paid, iban, rounds, reward)
+			}
+		}
+	}
+
+	if rollovers == 0 {
+		t.Fatal("Round never rolled over")
+	}
+
+	// Accrued reports rewards of the current round that have not been paid out.
+	for iban, reward := range dpos.Rewards {
+		account := ledger.Accounts[iban]
+
+		if accrued := dpos.Accrued(account.IBAN); accrued.Cmp(reward) != 0 {
+			t.Errorf("Accrued(%v) = %v, want %v", iban, accrued, reward)
+		}
+	}
+}
+
+func TestReward(t *testing.T) {
+	defer func(decay float64, rounds uint64) {
+		RewardDecay, RewardDecayRounds = decay, rounds
+	}(RewardDecay, RewardDecayRounds)
+
+	RewardDecay, RewardDecayRounds = 0.5, 10
+	tests := []struct {
+		rounds uint64
+		reward float64
+	}{
+		{0, 4},
+		{9, 4},
+		{10, 2},
+		{25, 1},
+		// ForgeReward never declines below MinForgeReward.
+		{30, 0.5},
+		{1000, 0.5},
+	}
+
+	dpos := NewDPoS()
+
+	for _, test := range tests {
+		dpos.Rounds = test.rounds
+
+		if reward := dpos.Reward(); reward.Cmp(primitives.NewAmount(test.reward)) != 0 {
+			t.Errorf("Reward of round %v = %v, want %v", test.rounds, reward, test.reward)
+		}
+	}
+
+	RewardDecayRounds = 0
+
+	if reward := dpos.Reward(); reward.Cmp(ForgeReward) != 0 {
+		t.Errorf("Reward without decay = %v, want %v", reward, ForgeReward)
+	}
+}
+
+// TestSupply checks that Supply accounts for every balance and unpaid amount over several rounds.
+func TestSupply(t *testing.T) {
+	defer func(forgers int, decay float64, rounds uint64) {
+		MaxForgers, RewardDecay, RewardDecayRounds = forgers, decay, rounds
+	}(MaxForgers, RewardDecay, RewardDecayRounds)
+
+	MaxForgers, RewardDecay, RewardDecayRounds = 3, 0.5, 50
+	test := newTestLedger(t)
+	ledger, node := test.ledger, test.node
+	dpos := node.DPoS
+	start := dpos.Rounds
+
+	check := func() {
+		t.Helper()
+		total := ledger.Circulating()
+		total.Add(total, unpaid(node))
+
+		if supply := ledger.Supply(); !closeTo(total, supply) {
+			t.Fatalf("Supply of round %v = %v, want balances and unpaid amounts of %v", dpos.Rounds, supply, total)
+		}
+	}
+
+	check()
+
+	for dpos.Rounds < start+8 {
+		mustTransfer(t, node, 1, test.alice, test.bob)
+		mustTransfer(t, node, 0.5, test.vault, test.alice)
+		check()
+	}
+
+	if _, err := node.ReceivePending(test.vault, test.signers...); err != nil {
+		t.Fatal(err)
+	}
+
+	check()
+
+	if (ledger.Burned.Sign() != 1) || (ledger.Minted.Sign() != 1) {
+		t.Errorf("Burned %v and minted %v, want both to be positive", ledger.Burned, ledger.Minted)
+	}
+
+	want := primitives.NewAmount(0)
+	want.Add(ledger.Genesis, ledger.Minted)
+	want.Sub(want, ledger.Burned)
+
+	if ledger.Supply().Cmp(want) != 0 {
+		t.Errorf("Supply = %v, want Genesis + Minted - Burned = %v", ledger.Supply(), want)
+	}
+
+	if dpos.Reward().Cmp(MinForgeReward) != 0 {
+		t.Errorf("Reward after %v rounds = %v, want %v", dpos.Rounds, dpos.Reward(), MinForgeReward)
+	}
+}
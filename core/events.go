@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/kookehs/watchmen/primitives"
+)
+
+// EventType is used to represent the different events emitted by a Node.
+type EventType uint8
+
+// Various event types
+const (
+	// BlockAppended is emitted for every block appended to the ledger by the Node.
+	BlockAppended EventType = iota
+	// RoundStarted is emitted when a new Round begins.
+	RoundStarted
+	// ForgerMissed is emitted when an unavailable forger misses its turn.
+	ForgerMissed
+	// DelegateRegistered is emitted when an account becomes a delegate.
+	DelegateRegistered
+	// VoteChanged is emitted when an account changes its delegates.
+	VoteChanged
+)
+
+// eventTypeNames contains the name of every EventType in order.
+var eventTypeNames = []string{"BlockAppended", "RoundStarted", "ForgerMissed", "DelegateRegistered", "VoteChanged"}
+
+// ParseEventType returns the EventType with the given name.
+func ParseEventType(name string) (EventType, error) {
+	for i, n := range eventTypeNames {
+		if strings.EqualFold(n, name) {
+			return EventType(i), nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown event type %q", name)
+}
+
+// MarshalText returns the name of the EventType.
+func (et EventType) MarshalText() ([]byte, error) {
+	return []byte(et.String()), nil
+}
+
+// String returns the name of the EventType.
+func (et EventType) String() string {
+	if int(et) < len(eventTypeNames) {
+		return eventTypeNames[et]
+	}
+
+	return fmt.Sprintf("EventType(%d)", uint8(et))
+}
+
+// UnmarshalText sets the EventType to the one with the given name.
+func (et *EventType) UnmarshalText(text []byte) error {
+	parsed, err := ParseEventType(string(text))
+
+	if err != nil {
+		return err
+	}
+
+	*et = parsed
+	return nil
+}
+
+// Event describes something that happened on a Node.
+// Only the fields relevant to the Type are set.
+type Event struct {
+	// Amount sent or received by the block
+	Amount    primitives.Amount `json:"amount,omitempty"`
+	Block     primitives.Block  `json:"block,omitempty"`
+	Delegates []primitives.IBAN `json:"delegates,omitempty"`
+	IBAN      IBAN              `json:"iban,omitempty"`
+	Round     uint64            `json:"round"`
+	Type      EventType         `json:"type"`
+}
+
+// Subscription receives events published to an EventBus on C.
+type Subscription struct {
+	C       <-chan Event
+	bus     *EventBus
+	c       chan Event
+	dropped uint64
+	types   map[EventType]bool
+}
+
+// Dropped returns the number of events discarded because C was full.
+func (s *Subscription) Dropped() uint64 {
+	s.bus.mutex.Lock()
+	defer s.bus.mutex.Unlock()
+	return s.dropped
+}
+
+// Close unsubscribes and closes C.
+func (s *Subscription) Close() {
+	s.bus.Unsubscribe(s)
+}
+
+// EventBus delivers published events to its subscribers.
+// Publishing never blocks so slow subscribers miss events rather than stalling the Node.
+type EventBus struct {
+	mutex       sync.Mutex
+	subscribers map[*Subscription]bool
+}
+
+// NewEventBus returns a pointer to an initialized EventBus.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[*Subscription]bool),
+	}
+}
+
+// Publish delivers the given event to every subscriber of its type.
+func (eb *EventBus) Publish(event Event) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+
+	for subscription := range eb.subscribers {
+		if (len(subscription.types) > 0) && !subscription.types[event.Type] {
+			continue
+		}
+
+		select {
+		case subscription.c <- event:
+		default:
+			subscription.dropped++
+		}
+	}
+}
+
+// Subscribe returns a Subscription buffering up to size events of the given types.
+// Events of every type are delivered when no types are given.
+func (eb *EventBus) Subscribe(size int, types ...EventType) *Subscription {
+	c := make(chan Event, size)
+	subscription := &Subscription{
+		C:     c,
+		bus:   eb,
+		c:     c,
+		types: make(map[EventType]bool),
+	}
+
+	for _, t := range types {
+		subscription.types[t] = true
+	}
+
+	eb.mutex.Lock()
+	eb.subscribers[subscription] = true
+	eb.mutex.Unlock()
+	return subscription
+}
+
+// Unsubscribe stops delivering events to the given Subscription and closes its channel.
+func (eb *EventBus) Unsubscribe(subscription *Subscription) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+
+	if _, exist := eb.subscribers[subscription]; !exist {
+		return
+	}
+
+	delete(eb.subscribers, subscription)
+	close(subscription.c)
+}
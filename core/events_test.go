@@ -0,0 +1,180 @@
+package core
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/kookehs/watchmen/primitives"
+)
+
+func TestEventType(t *testing.T) {
+	for i, name := range eventTypeNames {
+		et := EventType(i)
+
+		if et.String() != name {
+			t.Errorf("String of %d = %v, want %v", i, et.String(), name)
+		}
+
+		var parsed EventType
+
+		if err := parsed.UnmarshalText([]byte(name)); (err != nil) || (parsed != et) {
+			t.Errorf("UnmarshalText(%v) = %v, %v, want %v", name, parsed, err, et)
+		}
+	}
+
+	if et, err := ParseEventType("blockappended"); (err != nil) || (et != BlockAppended) {
+		t.Errorf("ParseEventType of lower case name = %v, %v, want %v", et, err, BlockAppended)
+	}
+
+	if _, err := ParseEventType("BlockForged"); err == nil {
+		t.Error("ParseEventType of unknown name succeeded")
+	}
+
+	if s := EventType(255).String(); s != "EventType(255)" {
+		t.Errorf("String of unknown type = %v, want EventType(255)", s)
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus()
+	all := bus.Subscribe(4)
+	blocks := bus.Subscribe(4, BlockAppended)
+	votes := bus.Subscribe(4, VoteChanged, DelegateRegistered)
+
+	events := []Event{{Type: RoundStarted, Round: 1}, {Type: BlockAppended, Round: 1}, {Type: VoteChanged, Round: 2}}
+
+	for _, event := range events {
+		bus.Publish(event)
+	}
+
+	tests := []struct {
+		name         string
+		subscription *Subscription
+		want         []Event
+	}{
+		{"every type", all, events},
+		{"BlockAppended", blocks, events[1:2]},
+		{"VoteChanged and DelegateRegistered", votes, events[2:]},
+	}
+
+	for _, test := range tests {
+		if len(test.subscription.C) != len(test.want) {
+			t.Errorf("Subscription to %v has %v events, want %v", test.name, len(test.subscription.C), len(test.want))
+			continue
+		}
+
+		for _, want := range test.want {
+			if got := <-test.subscription.C; (got.Type != want.Type) || (got.Round != want.Round) {
+				t.Errorf("Subscription to %v received %v of round %v, want %v of round %v", test.name, got.Type, got.Round, want.Type, want.Round)
+			}
+		}
+
+		if dropped := test.subscription.Dropped(); dropped != 0 {
+			t.Errorf("Subscription to %v dropped %v events", test.name, dropped)
+		}
+	}
+
+	// Unsubscribed channels are closed and no longer receive events.
+	blocks.Close()
+
+	if _, ok := <-blocks.C; ok {
+		t.Error("Channel of closed subscription is open")
+	}
+
+	bus.Publish(Event{Type: BlockAppended})
+	blocks.Close()
+	bus.Unsubscribe(blocks)
+
+	if len(all.C) != 1 {
+		t.Errorf("Subscription to every type has %v events after unsubscribe, want 1", len(all.C))
+	}
+}
+
+func TestEventBusDropsForSlowSubscribers(t *testing.T) {
+	bus := NewEventBus()
+	slow := bus.Subscribe(1)
+	fast := bus.Subscribe(8)
+
+	for round := uint64(0); round < 5; round++ {
+		bus.Publish(Event{Type: RoundStarted, Round: round})
+	}
+
+	if dropped := slow.Dropped(); dropped != 4 {
+		t.Errorf("Dropped = %v, want 4", dropped)
+	}
+
+	// The oldest event is kept since publishing never blocks or replaces buffered events.
+	if event := <-slow.C; event.Round != 0 {
+		t.Errorf("Buffered event of round %v, want 0", event.Round)
+	}
+
+	if (len(fast.C) != 5) || (fast.Dropped() != 0) {
+		t.Errorf("Subscription with room buffered %v events and dropped %v, want 5 and 0", len(fast.C), fast.Dropped())
+	}
+
+	unbuffered := bus.Subscribe(0)
+	bus.Publish(Event{Type: RoundStarted})
+
+	if dropped := unbuffered.Dropped(); dropped != 1 {
+		t.Errorf("Dropped of unbuffered subscription = %v, want 1", dropped)
+	}
+}
+
+func TestEventBusConcurrent(t *testing.T) {
+	bus := NewEventBus()
+	var wg sync.WaitGroup
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				bus.Publish(Event{Type: BlockAppended})
+			}
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				subscription := bus.Subscribe(1)
+				subscription.Dropped()
+				subscription.Close()
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestNodeEvents(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, node, alice, bob := test.ledger, test.node, test.alice, test.bob
+	subscription := node.Events.Subscribe(16, BlockAppended)
+	defer subscription.Close()
+
+	mustTransfer(t, node, 1, alice, bob)
+	appended := make(map[primitives.BlockHash]Event)
+
+	for len(subscription.C) > 0 {
+		event := <-subscription.C
+		appended[mustHash(t, event.Block)] = event
+	}
+
+	// The send and the receive it triggered are both published.
+	for _, account := range []*Account{bob, alice} {
+		latest := ledger.LatestBlock(account.IBAN)
+		event, exist := appended[mustHash(t, latest)]
+
+		if !exist {
+			t.Errorf("Latest block of %v was not published", account.IBAN.String())
+			continue
+		}
+
+		if (event.IBAN != account.IBAN.String()) || (event.Round != node.DPoS.Rounds) {
+			t.Errorf("Event of %v = %v of round %v, want %v of round %v", latest.Type(), event.IBAN, event.Round, account.IBAN.String(), node.DPoS.Rounds)
+		}
+	}
+}
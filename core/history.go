@@ -0,0 +1,219 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/kookehs/watchmen/primitives"
+)
+
+// Defines limits for history queries
+var (
+	// Number of blocks returned when no limit is given
+	DefaultHistoryLimit int = 50
+	MaxHistoryLimit     int = 1000
+)
+
+// ErrBlockNotFound is returned when no block has the given hash.
+var ErrBlockNotFound = errors.New("Block not found")
+
+// BlockFilter matches blocks of the listed types. An empty BlockFilter matches every block.
+type BlockFilter []primitives.BlockType
+
+// Match returns whether the given block is of one of the listed types.
+func (bf BlockFilter) Match(block primitives.Block) bool {
+	if len(bf) == 0 {
+		return true
+	}
+
+	for _, t := range bf {
+		if block.Type() == t {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BlockLocation is the position of a block within the chain of an account.
+type BlockLocation struct {
+	IBAN  IBAN `json:"iban"`
+	Index int  `json:"index"`
+}
+
+// HistoryPage is a page of blocks from the history of an account ordered from newest to oldest.
+// Next is the cursor of the following page and is zero once there are no older blocks.
+type HistoryPage struct {
+	Blocks []primitives.Block   `json:"blocks"`
+	Next   primitives.BlockHash `json:"next"`
+}
+
+// BlockByHash returns the block with the given hash from any account.
+func (l *Ledger) BlockByHash(hash primitives.BlockHash) (primitives.Block, error) {
+	location, exist := l.Index()[hash]
+
+	if !exist {
+		return nil, ErrBlockNotFound
+	}
+
+	return l.Blocks[location.IBAN][location.Index], nil
+}
+
+// History returns up to limit blocks of the given IBAN matching filter from newest to oldest.
+// The page starts after the block with the given cursor hash or at the latest block if cursor is zero.
+func (l *Ledger) History(iban primitives.IBAN, filter BlockFilter, cursor primitives.BlockHash, limit int) (*HistoryPage, error) {
+	blocks, exist := l.Blocks[iban.String()]
+
+	if !exist {
+		return nil, fmt.Errorf("Account for %v does not exist", iban.String())
+	}
+
+	if limit <= 0 {
+		limit = DefaultHistoryLimit
+	}
+
+	if limit > MaxHistoryLimit {
+		limit = MaxHistoryLimit
+	}
+
+	start := len(blocks) - 1
+
+	if cursor != primitives.BlockHashZero {
+		location, exist := l.Index()[cursor]
+
+		if !exist || (location.IBAN != iban.String()) {
+			return nil, errors.New("Cursor does not belong to the account")
+		}
+
+		start = location.Index - 1
+	}
+
+	page := &HistoryPage{
+		Blocks: make([]primitives.Block, 0, limit),
+		Next:   primitives.BlockHashZero,
+	}
+
+	for i := start; i >= 0; i-- {
+		if !filter.Match(blocks[i]) {
+			continue
+		}
+
+		if len(page.Blocks) == limit {
+			// Only hand out a cursor when there is another matching block.
+			hash, err := page.Blocks[limit-1].Hash()
+
+			if err != nil {
+				return nil, err
+			}
+
+			page.Next = hash
+			break
+		}
+
+		page.Blocks = append(page.Blocks, blocks[i])
+	}
+
+	return page, nil
+}
+
+// Index returns the location of every block by hash.
+// The index is built by NewLedger and Deserialize and kept up to date by AppendBlock.
+func (l *Ledger) Index() map[primitives.BlockHash]BlockLocation {
+	return l.index
+}
+
+// Locate returns the location of the block with the given hash.
+func (l *Ledger) Locate(hash primitives.BlockHash) (BlockLocation, bool) {
+	location, exist := l.Index()[hash]
+	return location, exist
+}
+
+// Receipt returns the hash of the ReceiveBlock that received the SendBlock with the given hash.
+func (l *Ledger) Receipt(src primitives.BlockHash) (primitives.BlockHash, bool) {
+	hash, exist := l.receipts[src]
+	return hash, exist
+}
+
+// ValidateReceive returns whether the given source block can be received by the given IBAN.
+// The source must be a SendBlock in the ledger to the IBAN that has not been received yet.
+func (l *Ledger) ValidateReceive(iban primitives.IBAN, src primitives.Block) error {
+	hash, err := src.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	location, exist := l.Locate(hash)
+
+	if !exist {
+		return errors.New("Source block does not exist")
+	}
+
+	send, ok := l.Blocks[location.IBAN][location.Index].(*primitives.SendBlock)
+
+	if !ok {
+		return errors.New("Source block is not a send block")
+	}
+
+	if send.Hashables.Destination != iban {
+		return errors.New("Source block was sent to another account")
+	}
+
+	if _, exist := l.Receipt(hash); exist {
+		return errors.New("Source block has already been received")
+	}
+
+	return nil
+}
+
+// buildIndex indexes every block in Blocks.
+func (l *Ledger) buildIndex() {
+	l.index = make(map[primitives.BlockHash]BlockLocation)
+	l.receipts = make(map[primitives.BlockHash]primitives.BlockHash)
+
+	for iban, blocks := range l.Blocks {
+		for i, block := range blocks {
+			hash, err := block.Hash()
+
+			if err != nil {
+				continue
+			}
+
+			l.indexBlock(hash, block, iban, i)
+		}
+	}
+}
+
+// indexBlock records the location of the given block and the source it received if any.
+// The authorized key of the Account is updated for OpenBlocks and RotateBlocks.
+func (l *Ledger) indexBlock(hash primitives.BlockHash, block primitives.Block, iban IBAN, index int) {
+	l.index[hash] = BlockLocation{
+		IBAN:  iban,
+		Index: index,
+	}
+
+	if (block.Type() == primitives.Receive) && (block.Source() != primitives.BlockHashZero) {
+		l.receipts[block.Source()] = hash
+	}
+
+	account, exist := l.Accounts[iban]
+
+	if !exist {
+		return
+	}
+
+	switch b := block.(type) {
+	case *primitives.OpenBlock:
+		if b.Hashables.Key != nil {
+			if key, err := b.Hashables.Key.Verifier(); err == nil {
+				account.authorized = key
+			}
+		}
+	case *primitives.RotateBlock:
+		next := b.Key()
+
+		if key, err := next.Verifier(); err == nil {
+			account.authorized = key
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package core
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/kookehs/watchmen/primitives"
+)
+
+func mustHash(t *testing.T, block primitives.Block) primitives.BlockHash {
+	t.Helper()
+	hash, err := block.Hash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash
+}
+
+// historyFrom pages through the history of the given IBAN and returns every block it was handed.
+func historyFrom(t *testing.T, ledger *Ledger, iban primitives.IBAN, filter BlockFilter, limit int) []primitives.Block {
+	t.Helper()
+	blocks := make([]primitives.Block, 0)
+	cursor := primitives.BlockHashZero
+
+	for {
+		page, err := ledger.History(iban, filter, cursor, limit)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(page.Blocks) > limit {
+			t.Fatalf("History returned %v blocks, want at most %v", len(page.Blocks), limit)
+		}
+
+		blocks = append(blocks, page.Blocks...)
+
+		if page.Next == primitives.BlockHashZero {
+			return blocks
+		}
+
+		if page.Next != mustHash(t, page.Blocks[len(page.Blocks)-1]) {
+			t.Fatal("Cursor is not the hash of the last block of the page")
+		}
+
+		cursor = page.Next
+	}
+}
+
+func TestHistory(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, node, alice, bob := test.ledger, test.node, test.alice, test.bob
+
+	for i := 0; i < 5; i++ {
+		mustTransfer(t, node, 1, alice, bob)
+	}
+
+	chain := ledger.Blocks[alice.IBAN.String()]
+	receives := 0
+
+	for _, block := range chain {
+		if block.Type() == primitives.Receive {
+			receives++
+		}
+	}
+
+	filters := []struct {
+		name   string
+		filter BlockFilter
+		want   int
+	}{
+		{"every block", nil, len(chain)},
+		{"receive blocks", BlockFilter{primitives.Receive}, receives},
+		{"send and receive blocks", BlockFilter{primitives.Send, primitives.Receive}, -1},
+	}
+
+	for _, tc := range filters {
+		for _, limit := range []int{1, 2, 3, len(chain)} {
+			blocks := historyFrom(t, ledger, alice.IBAN, tc.filter, limit)
+
+			if (tc.want >= 0) && (len(blocks) != tc.want) {
+				t.Errorf("History of %v with limit %v has %v blocks, want %v", tc.name, limit, len(blocks), tc.want)
+			}
+
+			// Blocks are handed out from newest to oldest without gaps or repeats.
+			next := len(chain) - 1
+
+			for _, block := range blocks {
+				for (next >= 0) && !tc.filter.Match(chain[next]) {
+					next--
+				}
+
+				if (next < 0) || (block != chain[next]) {
+					t.Fatalf("History of %v with limit %v is out of order", tc.name, limit)
+				}
+
+				next--
+			}
+
+			for ; next >= 0; next-- {
+				if tc.filter.Match(chain[next]) {
+					t.Errorf("History of %v with limit %v misses block %v", tc.name, limit, next)
+				}
+			}
+		}
+	}
+
+	// A page that ends exactly at the oldest matching block has no cursor.
+	page, err := ledger.History(alice.IBAN, nil, primitives.BlockHashZero, len(chain))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if (len(page.Blocks) != len(chain)) || (page.Next != primitives.BlockHashZero) {
+		t.Errorf("History with limit of chain length = %v blocks, next %v", len(page.Blocks), page.Next)
+	}
+}
+
+func TestHistoryLimits(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, alice := test.ledger, test.alice
+	length := len(ledger.Blocks[alice.IBAN.String()])
+
+	defer func(def, max int) {
+		DefaultHistoryLimit, MaxHistoryLimit = def, max
+	}(DefaultHistoryLimit, MaxHistoryLimit)
+
+	DefaultHistoryLimit, MaxHistoryLimit = 2, 3
+
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{0, 2},
+		{-1, 2},
+		{1, 1},
+		{3, 3},
+		{length, 3},
+	}
+
+	for _, test := range tests {
+		page, err := ledger.History(alice.IBAN, nil, primitives.BlockHashZero, test.limit)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(page.Blocks) != test.want {
+			t.Errorf("History with limit %v = %v blocks, want %v", test.limit, len(page.Blocks), test.want)
+		}
+
+		if page.Next == primitives.BlockHashZero {
+			t.Errorf("History with limit %v has no cursor", test.limit)
+		}
+	}
+
+	bobs := mustHash(t, ledger.LatestBlock(test.bob.IBAN))
+	invalid := []primitives.BlockHash{bobs, {1}}
+
+	for _, cursor := range invalid {
+		if _, err := ledger.History(alice.IBAN, nil, cursor, 1); err == nil {
+			t.Errorf("History with cursor %v succeeded", cursor)
+		}
+	}
+
+	if _, err := ledger.History(mustIBAN(t, 1), nil, primitives.BlockHashZero, 1); err == nil {
+		t.Error("History of unknown account succeeded")
+	}
+}
+
+func TestAppendBlockRejectsDuplicate(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, alice, bob := test.ledger, test.alice, test.bob
+	latest := ledger.LatestBlock(alice.IBAN)
+	length := len(ledger.Blocks[alice.IBAN.String()])
+
+	for _, iban := range []primitives.IBAN{alice.IBAN, bob.IBAN} {
+		if err := ledger.AppendBlock(latest, iban); err == nil {
+			t.Errorf("AppendBlock of existing block to %v succeeded", iban.String())
+		}
+	}
+
+	if len(ledger.Blocks[alice.IBAN.String()]) != length {
+		t.Error("Duplicate block was appended")
+	}
+
+	location, exist := ledger.Locate(mustHash(t, latest))
+
+	if !exist || (location != BlockLocation{IBAN: alice.IBAN.String(), Index: length - 1}) {
+		t.Errorf("Locate of latest block = %v, %v", location, exist)
+	}
+}
+
+func TestReceiveRejectsDoubleReceive(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, node, alice, bob := test.ledger, test.node, test.alice, test.bob
+
+	if _, err := node.Transfer(primitives.NewAmount(1), alice.IBAN, bob.IBAN); err != nil {
+		t.Fatal(err)
+	}
+
+	send := ledger.LatestBlock(bob.IBAN)
+	hash := mustHash(t, send)
+	receipt, exist := ledger.Receipt(hash)
+
+	if !exist {
+		t.Fatal("Send to alice has no receipt")
+	}
+
+	received, err := ledger.BlockByHash(receipt)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if (received.Type() != primitives.Receive) || (received.Source() != hash) {
+		t.Errorf("Receipt of send = %v block of %v, want receive of %v", received.Type(), received.Source(), hash)
+	}
+
+	if err := ledger.ValidateReceive(alice.IBAN, send); err == nil {
+		t.Error("ValidateReceive of received send succeeded")
+	}
+
+	length := len(ledger.Blocks[alice.IBAN.String()])
+	blueprint, err := alice.CreateReceiveBlock(primitives.NewAmount(1), nil, ledger.LatestBlock(alice.IBAN), send)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := node.Process(NewRequest(alice, blueprint)); err == nil {
+		t.Error("Process of second receive succeeded")
+	}
+
+	if len(ledger.Blocks[alice.IBAN.String()]) != length {
+		t.Error("Second receive was appended")
+	}
+
+	// Sends to multisig accounts wait until they are received.
+	if _, err := node.Transfer(primitives.NewAmount(1), test.vault.IBAN, bob.IBAN); err != nil {
+		t.Fatal(err)
+	}
+
+	pending := ledger.LatestBlock(bob.IBAN)
+
+	if _, exist := ledger.Receipt(mustHash(t, pending)); exist {
+		t.Error("Pending send has a receipt")
+	}
+
+	invalid := []struct {
+		name string
+		iban primitives.IBAN
+		src  primitives.Block
+	}{
+		{"by another account", alice.IBAN, pending},
+		{"of a block that is not a send", test.vault.IBAN, ledger.LatestBlock(alice.IBAN)},
+		{"of an unknown block", test.vault.IBAN, primitives.NewSendBlock(primitives.NewAmount(1), test.vault.IBAN, primitives.BlockHash{1})},
+	}
+
+	for _, tc := range invalid {
+		if err := ledger.ValidateReceive(tc.iban, tc.src); err == nil {
+			t.Errorf("ValidateReceive %v succeeded", tc.name)
+		}
+	}
+
+	if err := ledger.ValidateReceive(test.vault.IBAN, pending); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := node.ReceivePending(test.vault, test.signers...); err != nil {
+		t.Fatal(err)
+	}
+
+	// Receivables re-added for a received send are dropped rather than received again.
+	length = len(ledger.Blocks[test.vault.IBAN.String()])
+	ledger.AddPending(test.vault.IBAN, primitives.NewAmount(1), pending)
+	blocks, err := node.ReceivePending(test.vault, test.signers...)
+
+	if (err != nil) || (len(blocks) != 0) {
+		t.Errorf("ReceivePending of received send = %v blocks, %v", len(blocks), err)
+	}
+
+	if len(ledger.Blocks[test.vault.IBAN.String()]) != length {
+		t.Error("Received send was received again")
+	}
+}
+
+func TestIndexAfterImport(t *testing.T) {
+	test := newTestLedger(t)
+	data, err := json.Marshal(test.ledger)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported := &Ledger{}
+
+	if err := json.Unmarshal(data, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(imported.Index()) != len(test.ledger.Index()) {
+		t.Errorf("Imported index has %v blocks, want %v", len(imported.Index()), len(test.ledger.Index()))
+	}
+
+	for hash, location := range test.ledger.Index() {
+		if got, exist := imported.Locate(hash); !exist || (got != location) {
+			t.Errorf("Locate(%v) = %v, %v, want %v", hash, got, exist, location)
+		}
+	}
+
+	for src, want := range test.ledger.receipts {
+		if got, exist := imported.Receipt(src); !exist || (got != want) {
+			t.Errorf("Receipt(%v) = %v, %v, want %v", src, got, exist, want)
+		}
+	}
+}
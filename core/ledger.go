@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/gob"
 	"encoding/json"
@@ -8,9 +9,11 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"math/big"
 	"strconv"
 	"strings"
 
+	"github.com/kookehs/watchmen/crypto"
 	"github.com/kookehs/watchmen/primitives"
 )
 
@@ -20,32 +23,231 @@ type IBAN = string
 // Username is a type alias used for readability and JSON purposes.
 type Username = string
 
+// Defines the initial supply and key scheme of the system
+var (
+	GenesisSupply primitives.Amount = primitives.NewAmount(100000000)
+
+	// Signature scheme used for the keys of newly opened accounts
+	KeyScheme crypto.Scheme = crypto.P256
+)
+
 // Ledger is the structure in which we record accounts and block.
 type Ledger struct {
-	Accounts map[IBAN]*Account            `json:"accounts"`
-	Blocks   map[IBAN][]primitives.Block  `json:"blocks"`
-	Users    map[Username]primitives.IBAN `json:"users"`
+	Accounts map[IBAN]*Account `json:"accounts"`
+	// Blocks of every account encoded in JSON with their types
+	Blocks  map[IBAN]primitives.Blocks `json:"blocks"`
+	Burned  primitives.Amount          `json:"burned"`
+	Genesis primitives.Amount          `json:"genesis"`
+	Minted  primitives.Amount          `json:"minted"`
+	// Amounts waiting to be received by multisig accounts
+	Pending map[IBAN][]*Receivable `json:"pending"`
+	Users   *Registry              `json:"users"`
+	// Location of every block by hash built from Blocks
+	index map[primitives.BlockHash]BlockLocation
+	// Hash of the ReceiveBlock by the hash of the SendBlock it received
+	receipts map[primitives.BlockHash]primitives.BlockHash
+}
+
+// LedgerVersion is the version of the JSON format written by Ledger.MarshalJSON.
+const LedgerVersion = 1
+
+// ledgerJSON is the versioned JSON representation of a Ledger.
+type ledgerJSON struct {
+	Accounts map[IBAN]*Account          `json:"accounts"`
+	Blocks   map[IBAN]primitives.Blocks `json:"blocks"`
+	Burned   primitives.Amount          `json:"burned"`
+	Genesis  primitives.Amount          `json:"genesis"`
+	Minted   primitives.Amount          `json:"minted"`
+	Pending  map[IBAN][]*Receivable     `json:"pending"`
+	Users    *Registry                  `json:"users"`
+	Version  int                        `json:"version"`
+}
+
+// Receivable is an amount sent to a multisig account that has not been received yet.
+// Source is nil for rewards.
+type Receivable struct {
+	Amount primitives.Amount `json:"amount"`
+	Source primitives.Block  `json:"source"`
+}
+
+// receivableJSON is the JSON representation of a Receivable with the type of its source.
+type receivableJSON struct {
+	Amount primitives.Amount        `json:"amount"`
+	Source primitives.BlockEnvelope `json:"source"`
+}
+
+// MarshalJSON encodes the Receivable wrapping its source in a BlockEnvelope.
+func (r *Receivable) MarshalJSON() ([]byte, error) {
+	return json.Marshal(receivableJSON{
+		Amount: r.Amount,
+		Source: primitives.BlockEnvelope{Block: r.Source},
+	})
+}
+
+// UnmarshalJSON decodes a Receivable whose source is wrapped in a BlockEnvelope.
+func (r *Receivable) UnmarshalJSON(data []byte) error {
+	decoded := receivableJSON{
+		Amount: new(big.Float).SetPrec(primitives.AmountPrecision),
+	}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	r.Amount = decoded.Amount
+	r.Source = decoded.Source.Block
+	return nil
 }
 
 // NewLedger creates and initializes a Ledger for storage of accounts and blocks.
 func NewLedger() *Ledger {
 	return &Ledger{
 		Accounts: make(map[IBAN]*Account),
-		Blocks:   make(map[IBAN][]primitives.Block),
-		Users:    make(map[Username]primitives.IBAN),
+		Blocks:   make(map[IBAN]primitives.Blocks),
+		Burned:   primitives.NewAmount(0),
+		Genesis:  primitives.NewAmount(0),
+		Minted:   primitives.NewAmount(0),
+		Pending:  make(map[IBAN][]*Receivable),
+		Users:    NewRegistry(),
+		index:    make(map[primitives.BlockHash]BlockLocation),
+		receipts: make(map[primitives.BlockHash]primitives.BlockHash),
+	}
+}
+
+// AddPending records the given amount as waiting to be received by the given IBAN.
+func (l *Ledger) AddPending(iban primitives.IBAN, amt primitives.Amount, src primitives.Block) {
+	amount := primitives.NewAmount(0)
+	amount.Copy(amt)
+	receivable := &Receivable{
+		Amount: amount,
+		Source: src,
 	}
+
+	l.Pending[iban.String()] = append(l.Pending[iban.String()], receivable)
 }
 
 // AppendBlock appends the given block to the given IBAN's chain.
+// Blocks must be unique and follow the latest block of the chain.
 func (l *Ledger) AppendBlock(block primitives.Block, iban primitives.IBAN) error {
 	if block == nil {
 		return errors.New("Cannot append nil block")
 	}
 
+	// Ledgers that were not created by NewLedger or Deserialize are indexed before their first append.
+	if l.index == nil {
+		l.buildIndex()
+	}
+
+	hash, err := block.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	if _, exist := l.Locate(hash); exist {
+		return errors.New("Block already exists")
+	}
+
+	if latest := l.LatestBlock(iban); latest == nil {
+		if block.Type() != primitives.Open {
+			return errors.New("Chain must start with an open block")
+		}
+	} else {
+		previous, err := latest.Hash()
+
+		if err != nil {
+			return err
+		}
+
+		if block.Previous() != previous {
+			return errors.New("Block does not follow the latest block of the chain")
+		}
+	}
+
+	if err := l.verifyBlock(block, iban); err != nil {
+		return err
+	}
+
 	l.Blocks[iban.String()] = append(l.Blocks[iban.String()], block)
+	l.indexBlock(hash, block, iban.String(), len(l.Blocks[iban.String()])-1)
 	return nil
 }
 
+// AuthorizedKey returns the key currently authorized to sign blocks for the given IBAN.
+// The key cached on the Account is returned if set. Otherwise the chain is replayed
+// from the key recorded in the OpenBlock through every verified RotateBlock.
+func (l *Ledger) AuthorizedKey(iban primitives.IBAN) (crypto.Verifier, error) {
+	if account, exist := l.Accounts[iban.String()]; exist && (account.authorized != nil) {
+		return account.authorized, nil
+	}
+
+	blocks := l.Blocks[iban.String()]
+
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("Account for %v does not exist", iban.String())
+	}
+
+	key, err := l.OpenKey(iban)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, block := range blocks[1:] {
+		rotate, ok := block.(*primitives.RotateBlock)
+
+		if !ok {
+			continue
+		}
+
+		if !rotate.VerifyAuthorization(key) {
+			return nil, errors.New("Key rotation was not authorized by the previous key")
+		}
+
+		next := rotate.Key()
+
+		if key, err = next.Verifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+// Burn removes the given amount from circulation.
+func (l *Ledger) Burn(amt primitives.Amount) {
+	l.Burned.Add(l.Burned, amt)
+}
+
+// Circulating returns the sum of the latest balances of all accounts.
+// Rewards that have been minted but not yet paid out are not included.
+func (l *Ledger) Circulating() primitives.Amount {
+	circulating := primitives.NewAmount(0)
+
+	for _, blocks := range l.Blocks {
+		if len(blocks) == 0 {
+			continue
+		}
+
+		circulating.Add(circulating, blocks[len(blocks)-1].Balance())
+	}
+
+	return circulating
+}
+
+// Mint records the creation of the given amount as a reward.
+func (l *Ledger) Mint(amt primitives.Amount) {
+	l.Minted.Add(l.Minted, amt)
+}
+
+// Supply returns the total amount created by genesis and rewards minus the amount burned.
+func (l *Ledger) Supply() primitives.Amount {
+	supply := primitives.NewAmount(0)
+	supply.Add(l.Genesis, l.Minted)
+	supply.Sub(supply, l.Burned)
+	return supply
+}
+
 // LatestBlock returns the newest block in the ledger with the given IBAN.
 func (l *Ledger) LatestBlock(iban primitives.IBAN) primitives.Block {
 	blocks, ok := l.Blocks[iban.String()]
@@ -57,13 +259,37 @@ func (l *Ledger) LatestBlock(iban primitives.IBAN) primitives.Block {
 	return nil
 }
 
-// OpenAccount creates an Account for the given username.
-func (l *Ledger) OpenAccount(node *Node, username string) (*Account, error) {
-	if _, exist := l.Users[username]; exist {
+// OpenKey returns the key recorded in the OpenBlock of the given IBAN from which its Address is derived.
+func (l *Ledger) OpenKey(iban primitives.IBAN) (crypto.Verifier, error) {
+	blocks := l.Blocks[iban.String()]
+
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("Account for %v does not exist", iban.String())
+	}
+
+	open, ok := blocks[0].(*primitives.OpenBlock)
+
+	if !ok || (open.Hashables.Key == nil) {
+		return nil, errors.New("Open block does not record a key")
+	}
+
+	return open.Hashables.Key.Verifier()
+}
+
+// openAccount creates an Account for the given username.
+// The username is registered with a NameBlock following the OpenBlock.
+func (l *Ledger) openAccount(node *Node, username string) (*Account, error) {
+	username = strings.ToLower(username)
+
+	if err := ValidateUsername(username); err != nil {
+		return nil, err
+	}
+
+	if _, exist := l.Users.IBAN(username); exist {
 		return nil, fmt.Errorf("Account for %v already exists", username)
 	}
 
-	key, err := primitives.NewKeyForICAP(rand.Reader)
+	key, err := primitives.NewSchemeKeyForICAP(KeyScheme, rand.Reader)
 
 	if err != nil {
 		return nil, err
@@ -77,51 +303,105 @@ func (l *Ledger) OpenAccount(node *Node, username string) (*Account, error) {
 		return nil, err
 	}
 
+	l.Accounts[account.IBAN.String()] = account
+	request := NewRequest(account, blueprint)
+
+	if _, err := node.process(request); err != nil {
+		delete(l.Accounts, account.IBAN.String())
+		return nil, err
+	}
+
+	// The account has no funds yet so the username it is opened with is registered without a fee.
+	if _, err := l.updateUsername(account, primitives.RegisterName, username, primitives.IBAN{}, primitives.NewAmount(0), node, nil); err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+// openMultisigAccount creates an Account for the given username controlled by the given Policy.
+// The OpenBlock declaring the policy and every following block must be signed by enough of its keys.
+func (l *Ledger) openMultisigAccount(node *Node, username string, policy *primitives.Policy, signers []crypto.Signer) (*Account, error) {
 	username = strings.ToLower(username)
-	l.Users[username] = account.IBAN
+
+	if err := ValidateUsername(username); err != nil {
+		return nil, err
+	}
+
+	if _, exist := l.Users.IBAN(username); exist {
+		return nil, fmt.Errorf("Account for %v already exists", username)
+	}
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	account := NewMultisigAccount(policy)
+
+	if _, exist := l.Accounts[account.IBAN.String()]; exist {
+		return nil, errors.New("Account for policy already exists")
+	}
+
+	amount := primitives.NewAmount(0)
+	blueprint, err := account.CreateOpenBlock(amount)
+
+	if err != nil {
+		return nil, err
+	}
+
 	l.Accounts[account.IBAN.String()] = account
 
-	request := NewRequest(account, blueprint)
+	if _, err := node.process(NewMultisigRequest(account, blueprint, signers)); err != nil {
+		delete(l.Accounts, account.IBAN.String())
+		return nil, err
+	}
 
-	if _, err := node.Process(request); err != nil {
+	// The account has no funds yet so the username it is opened with is registered without a fee.
+	if _, err := l.updateUsername(account, primitives.RegisterName, username, primitives.IBAN{}, primitives.NewAmount(0), node, signers); err != nil {
 		return nil, err
 	}
 
 	return account, nil
 }
 
-// OpenGenesisAccount creates an initial account that bypasses the system.
+// openGenesisAccount creates an initial account that bypasses the system.
 // Creates an account with an initial amount with delegate status.
 // This method is meant to be called once to initialize the system.
-func (l *Ledger) OpenGenesisAccount(username string) (*Account, error) {
-	if _, exist := l.Users[username]; exist {
+func (l *Ledger) openGenesisAccount(username string) (*Account, error) {
+	username = strings.ToLower(username)
+
+	if err := ValidateUsername(username); err != nil {
+		return nil, err
+	}
+
+	if _, exist := l.Users.IBAN(username); exist {
 		return nil, fmt.Errorf("Account for %v already exists", username)
 	}
 
-	key, err := primitives.NewKeyForICAP(rand.Reader)
+	key, err := primitives.NewSchemeKeyForICAP(KeyScheme, rand.Reader)
 
 	if err != nil {
 		return nil, err
 	}
 
 	account := NewAccount(key)
-	amount := primitives.NewAmount(100000000)
-	open := primitives.NewOpenBlock(amount, account.IBAN)
+	amount := primitives.NewAmount(0)
+	amount.Copy(GenesisSupply)
+	pub := primitives.MakePublicKey(account.Key.Verifier())
+	open := primitives.NewOpenBlock(amount, account.IBAN, &pub)
 
 	if open == nil {
 		return nil, errors.New("Unable to create block")
 	}
 
-	username = strings.ToLower(username)
-	l.Users[username] = account.IBAN
 	l.Accounts[account.IBAN.String()] = account
 
-	if err := open.Sign(account.Key.PrivateKey); err != nil {
+	if err := open.Sign(account.Key.Signer()); err != nil {
 		return nil, err
 	}
 
 	// Self-sign the opening block for the genesis account.
-	if err := open.SignWitness(account.Key.PrivateKey); err != nil {
+	if err := open.SignWitness(account.Key.Signer()); err != nil {
 		return nil, err
 	}
 
@@ -129,6 +409,8 @@ func (l *Ledger) OpenGenesisAccount(username string) (*Account, error) {
 		return nil, err
 	}
 
+	l.Genesis.Add(l.Genesis, amount)
+
 	prev := l.LatestBlock(account.IBAN)
 	hash, err := prev.Hash()
 
@@ -136,6 +418,36 @@ func (l *Ledger) OpenGenesisAccount(username string) (*Account, error) {
 		return nil, err
 	}
 
+	name := primitives.NewNameBlock(prev.Balance(), primitives.RegisterName, username, primitives.IBAN{}, hash)
+
+	if name == nil {
+		return nil, errors.New("Unable to create block")
+	}
+
+	if err := name.Sign(account.Key.Signer()); err != nil {
+		return nil, err
+	}
+
+	// Self-sign the name block for the genesis account.
+	if err := name.SignWitness(account.Key.Signer()); err != nil {
+		return nil, err
+	}
+
+	if err := l.AppendBlock(name, account.IBAN); err != nil {
+		return nil, err
+	}
+
+	if err := l.Users.Register(username, account.IBAN); err != nil {
+		return nil, err
+	}
+
+	prev = l.LatestBlock(account.IBAN)
+	hash, err = prev.Hash()
+
+	if err != nil {
+		return nil, err
+	}
+
 	share := 100.0
 	delegate := primitives.NewDelegateBlock(prev.Balance(), hash, share)
 
@@ -143,12 +455,12 @@ func (l *Ledger) OpenGenesisAccount(username string) (*Account, error) {
 		return nil, errors.New("Unable to create block")
 	}
 
-	if err := delegate.Sign(account.Key.PrivateKey); err != nil {
+	if err := delegate.Sign(account.Key.Signer()); err != nil {
 		return nil, err
 	}
 
 	// Self-sign the opening block for the genesis account.
-	if err := delegate.SignWitness(account.Key.PrivateKey); err != nil {
+	if err := delegate.SignWitness(account.Key.Signer()); err != nil {
 		return nil, err
 	}
 
@@ -170,12 +482,12 @@ func (l *Ledger) OpenGenesisAccount(username string) (*Account, error) {
 		return nil, errors.New("Unable to create block")
 	}
 
-	if err := change.Sign(account.Key.PrivateKey); err != nil {
+	if err := change.Sign(account.Key.Signer()); err != nil {
 		return nil, err
 	}
 
 	// Self-sign the opening block for the genesis account.
-	if err := change.SignWitness(account.Key.PrivateKey); err != nil {
+	if err := change.SignWitness(account.Key.Signer()); err != nil {
 		return nil, err
 	}
 
@@ -187,10 +499,10 @@ func (l *Ledger) OpenGenesisAccount(username string) (*Account, error) {
 	return account, nil
 }
 
-// OpenGenesisDelegates creates the initial MaxDelegatesPerAccount delegates.
+// openGenesisDelegates creates the initial MaxDelegatesPerAccount delegates.
 // This receiver should be called after creating the genesis account.
 // The process follows the rules of the system returning the delegates.
-func (l *Ledger) OpenGenesisDelegates(dpos *DPoS, genesis *Account, node *Node) []*Account {
+func (l *Ledger) openGenesisDelegates(dpos *DPoS, genesis *Account, node *Node) []*Account {
 	delegates := make([]*Account, MaxDelegatesPerAccount)
 
 	split := primitives.NewAmount(0)
@@ -199,21 +511,21 @@ func (l *Ledger) OpenGenesisDelegates(dpos *DPoS, genesis *Account, node *Node)
 
 	fee := primitives.NewAmount(0)
 	ways := primitives.NewAmount(float64(MaxDelegatesPerAccount))
-	fee.Mul(ways, TransactionFee)
+	fee.Mul(ways, node.Fee())
 
 	split.Sub(split, fee)
 	split.Quo(split, ways)
 
 	for i := 0; i < MaxDelegatesPerAccount; i++ {
 		username := "genesis_" + strconv.Itoa(i+1)
-		delegate, err := l.OpenAccount(node, username)
+		delegate, err := l.openAccount(node, username)
 
 		if err != nil {
 			log.Println(err)
 			continue
 		}
 
-		_, err = l.Transfer(split, delegate.IBAN, genesis.IBAN, node)
+		_, err = l.transfer(split, delegate.IBAN, genesis.IBAN, node, nil)
 
 		if err != nil {
 			log.Println(err)
@@ -222,19 +534,19 @@ func (l *Ledger) OpenGenesisDelegates(dpos *DPoS, genesis *Account, node *Node)
 
 		prev := l.LatestBlock(delegate.IBAN)
 		share := 100.0
-		blueprint, err := delegate.CreateDelegateBlock(prev, share)
+		blueprint, err := delegate.CreateDelegateBlock(node.Fee(), prev, share)
 
 		if err != nil {
 			log.Println(err)
 			continue
 		}
 
-		if _, err = node.Process(NewRequest(delegate, blueprint)); err != nil {
+		if _, err = node.process(NewRequest(delegate, blueprint)); err != nil {
 			log.Println(err)
 			continue
 		}
 
-		if err := dpos.Elect(delegate, []string{"+" + username}, l, node); err != nil {
+		if err := dpos.elect(delegate, []string{"+" + username}, l, node, nil); err != nil {
 			log.Println(err)
 			continue
 		}
@@ -245,6 +557,111 @@ func (l *Ledger) OpenGenesisDelegates(dpos *DPoS, genesis *Account, node *Node)
 	return delegates
 }
 
+// receivePending receives every amount waiting for the given multisig Account with the given signers.
+// Amounts that fail to be received remain pending.
+func (l *Ledger) receivePending(account *Account, node *Node, signers []crypto.Signer) ([]primitives.Block, error) {
+	pending := l.Pending[account.IBAN.String()]
+	blocks := make([]primitives.Block, 0, len(pending))
+
+	for len(pending) > 0 {
+		receivable := pending[0]
+
+		if receivable.Source != nil {
+			if err := l.ValidateReceive(account.IBAN, receivable.Source); err != nil {
+				log.Println(err)
+				pending = pending[1:]
+				l.Pending[account.IBAN.String()] = pending
+				continue
+			}
+		}
+
+		prev := l.LatestBlock(account.IBAN)
+		blueprint, err := account.CreateReceiveBlock(receivable.Amount, nil, prev, receivable.Source)
+
+		if err != nil {
+			return blocks, err
+		}
+
+		block, err := node.process(NewMultisigRequest(account, blueprint, signers))
+
+		if err != nil {
+			return blocks, err
+		}
+
+		pending = pending[1:]
+		l.Pending[account.IBAN.String()] = pending
+		blocks = append(blocks, block)
+	}
+
+	delete(l.Pending, account.IBAN.String())
+	return blocks, nil
+}
+
+// registerUsername records the registration of the given username to the given Account paying the current transaction fee.
+// Signers are only required for multisig accounts.
+func (l *Ledger) registerUsername(account *Account, username string, node *Node, signers []crypto.Signer) (primitives.Block, error) {
+	return l.updateUsername(account, primitives.RegisterName, strings.ToLower(username), primitives.IBAN{}, node.Fee(), node, signers)
+}
+
+// releaseUsername records the release of the username owned by the given Account.
+func (l *Ledger) releaseUsername(account *Account, node *Node, signers []crypto.Signer) (primitives.Block, error) {
+	username, exist := l.Users.Username(account.IBAN)
+
+	if !exist {
+		return nil, errors.New("Account does not have a username")
+	}
+
+	return l.updateUsername(account, primitives.ReleaseName, username, primitives.IBAN{}, node.Fee(), node, signers)
+}
+
+// transferUsername records the transfer of the username owned by the given Account to dst.
+func (l *Ledger) transferUsername(account *Account, dst primitives.IBAN, node *Node, signers []crypto.Signer) (primitives.Block, error) {
+	username, exist := l.Users.Username(account.IBAN)
+
+	if !exist {
+		return nil, errors.New("Account does not have a username")
+	}
+
+	if _, exist := l.Accounts[dst.String()]; !exist {
+		return nil, errors.New("Destination account does not exist")
+	}
+
+	return l.updateUsername(account, primitives.TransferName, username, dst, node.Fee(), node, signers)
+}
+
+// updateUsername checks the given action against the registry and processes a NameBlock paying the given fee for it.
+func (l *Ledger) updateUsername(account *Account, action primitives.NameAction, username string, dst primitives.IBAN, fee primitives.Amount, node *Node, signers []crypto.Signer) (primitives.Block, error) {
+	if err := l.Users.Check(action, username, dst, account.IBAN); err != nil {
+		return nil, err
+	}
+
+	prev := l.LatestBlock(account.IBAN)
+	blueprint, err := account.CreateNameBlock(action, username, dst, fee, prev)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return node.process(NewMultisigRequest(account, blueprint, signers))
+}
+
+// rotateKey authorizes the given key to sign blocks for the Account in place of its current key.
+// The IBAN of the Account remains derived from the key it was opened with.
+func (l *Ledger) rotateKey(account *Account, key *primitives.Key, node *Node) (primitives.Block, error) {
+	if account.Multisig() {
+		return nil, errors.New("Multisig accounts cannot rotate keys")
+	}
+
+	prev := l.LatestBlock(account.IBAN)
+	blueprint, err := account.CreateRotateBlock(key, prev)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return node.process(NewRequest(account, blueprint))
+}
+
 // Stakeholders returns a list of accounts who elected the given delegate.
 func (l *Ledger) Stakeholders(delegate primitives.IBAN) []*Account {
 	stakeholders := make([]*Account, 0)
@@ -258,16 +675,31 @@ func (l *Ledger) Stakeholders(delegate primitives.IBAN) []*Account {
 	return stakeholders
 }
 
-func (l *Ledger) Transfer(amt primitives.Amount, dst, src primitives.IBAN, node *Node) (primitives.Block, error) {
+// transfer sends the given amount from src to dst paying the current transaction fee.
+// Signers are only required when src is a multisig account.
+func (l *Ledger) transfer(amt primitives.Amount, dst, src primitives.IBAN, node *Node, signers []crypto.Signer) (primitives.Block, error) {
+	if _, exist := l.Accounts[dst.String()]; !exist {
+		return nil, fmt.Errorf("Account for %v does not exist", dst.String())
+	}
+
+	if !l.VerifyIBAN(dst) {
+		return nil, errors.New("Destination IBAN does not belong to the recipient's key")
+	}
+
+	account, exist := l.Accounts[src.String()]
+
+	if !exist {
+		return nil, fmt.Errorf("Account for %v does not exist", src.String())
+	}
+
 	prev := l.LatestBlock(src)
-	account := l.Accounts[src.String()]
-	blueprint, err := account.CreateSendBlock(amt, dst, prev)
+	blueprint, err := account.CreateSendBlock(amt, node.Fee(), dst, prev)
 
 	if err != nil {
 		return nil, err
 	}
 
-	block, err := node.Process(NewRequest(account, blueprint))
+	block, err := node.process(NewMultisigRequest(account, blueprint, signers))
 
 	if err != nil {
 		return nil, err
@@ -278,38 +710,270 @@ func (l *Ledger) Transfer(amt primitives.Amount, dst, src primitives.IBAN, node
 
 // Username returns the username associated with the given IBAN.
 func (l *Ledger) Username(iban primitives.IBAN) string {
-	var username string
+	username, _ := l.Users.Username(iban)
+	return username
+}
 
-	for key, value := range l.Users {
-		if value == iban {
-			username = key
-			break
+// VerifyIBAN returns whether the given IBAN is the direct ICAP encoding of the Address of its Account.
+// Addresses are derived from the policy of multisig accounts and from the key in the OpenBlock otherwise.
+func (l *Ledger) VerifyIBAN(iban primitives.IBAN) bool {
+	account, exist := l.Accounts[iban.String()]
+
+	if !exist {
+		return false
+	}
+
+	if account.Multisig() {
+		address, err := iban.Address()
+		return (err == nil) && (address == account.Policy.Address())
+	}
+
+	key, err := l.OpenKey(iban)
+
+	if err != nil {
+		return false
+	}
+
+	return primitives.VerifyIBAN(iban, key)
+}
+
+// verifyBlock returns whether the given block is signed by the owner of the chain of the given IBAN.
+// OpenBlocks are signed by the key they record and RotateBlocks by the key they authorize
+// with an authorization from the current key. Other blocks are verified by the Account.
+// OpenBlocks of multisig accounts must declare the Policy of the Account.
+func (l *Ledger) verifyBlock(block primitives.Block, iban primitives.IBAN) error {
+	account, exist := l.Accounts[iban.String()]
+
+	if !exist {
+		return fmt.Errorf("Account for %v does not exist", iban.String())
+	}
+
+	if account.Multisig() {
+		if open, ok := block.(*primitives.OpenBlock); ok {
+			if (open.Hashables.Policy == nil) || (open.Hashables.Policy.Address() != account.Policy.Address()) {
+				return errors.New("Open block does not declare the policy of the account")
+			}
 		}
+
+		return account.Verify(block)
 	}
 
-	return username
+	switch b := block.(type) {
+	case *primitives.OpenBlock:
+		if b.Hashables.Key == nil {
+			return errors.New("Open block does not record a key")
+		}
+
+		key, err := b.Hashables.Key.Verifier()
+
+		if err != nil {
+			return err
+		}
+
+		return VerifyBlock(block, key)
+	case *primitives.RotateBlock:
+		current, err := account.AuthorizedKey()
+
+		if err != nil {
+			return err
+		}
+
+		if !b.VerifyAuthorization(current) {
+			return errors.New("Key rotation was not authorized by the previous key")
+		}
+
+		next := b.Key()
+		key, err := next.Verifier()
+
+		if err != nil {
+			return err
+		}
+
+		return VerifyBlock(block, key)
+	default:
+		return account.Verify(block)
+	}
 }
 
 // Deserialize decodes byte data encoded by gob.
+// The block index and authorized keys are rebuilt from the decoded chains.
 func (l *Ledger) Deserialize(r io.Reader) error {
 	decoder := gob.NewDecoder(r)
-	return decoder.Decode(l)
+
+	if err := decoder.Decode(l); err != nil {
+		return err
+	}
+
+	l.buildIndex()
+	return nil
 }
 
-// DeserializeJSON decodes JSON data.
+// DeserializeJSON decodes JSON data written by SerializeJSON.
 func (l *Ledger) DeserializeJSON(r io.Reader) error {
 	decoder := json.NewDecoder(r)
 	return decoder.Decode(l)
 }
 
+// MarshalJSON encodes the Ledger in the format of LedgerVersion without private keys.
+// Public keys are recorded by the chains so accounts can still be verified once imported.
+func (l *Ledger) MarshalJSON() ([]byte, error) {
+	accounts := make(map[IBAN]*Account, len(l.Accounts))
+
+	for iban, account := range l.Accounts {
+		public := *account
+		public.Key = nil
+		accounts[iban] = &public
+	}
+
+	return l.marshalJSON(accounts)
+}
+
+// MarshalJSONWithKeys encodes the Ledger in the format of LedgerVersion including the private key of every account.
+// The output must be kept private.
+func (l *Ledger) MarshalJSONWithKeys() ([]byte, error) {
+	return l.marshalJSON(l.Accounts)
+}
+
+// marshalJSON encodes the Ledger with the given accounts.
+func (l *Ledger) marshalJSON(accounts map[IBAN]*Account) ([]byte, error) {
+	return json.Marshal(ledgerJSON{
+		Accounts: accounts,
+		Blocks:   l.Blocks,
+		Burned:   l.Burned,
+		Genesis:  l.Genesis,
+		Minted:   l.Minted,
+		Pending:  l.Pending,
+		Users:    l.Users,
+		Version:  LedgerVersion,
+	})
+}
+
 // Serialize encodes to byte data using gob.
 func (l *Ledger) Serialize(w io.Writer) error {
 	encoder := gob.NewEncoder(w)
 	return encoder.Encode(l)
 }
 
-// SerializeJSON encodes to JSON data.
+// SerializeJSON encodes to JSON data without private keys.
 func (l *Ledger) SerializeJSON(w io.Writer) error {
 	encoder := json.NewEncoder(w)
 	return encoder.Encode(l)
 }
+
+// SerializeJSONWithKeys encodes to JSON data including private keys.
+func (l *Ledger) SerializeJSONWithKeys(w io.Writer) error {
+	data, err := l.MarshalJSONWithKeys()
+
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// UnmarshalJSON decodes a Ledger encoded by MarshalJSON or MarshalJSONWithKeys.
+// Every chain is appended to a new Ledger so that blocks are validated and indexed.
+// Private keys that are included must match the keys authorized by the chains.
+func (l *Ledger) UnmarshalJSON(data []byte) error {
+	decoded := ledgerJSON{
+		Burned:  new(big.Float).SetPrec(primitives.AmountPrecision),
+		Genesis: new(big.Float).SetPrec(primitives.AmountPrecision),
+		Minted:  new(big.Float).SetPrec(primitives.AmountPrecision),
+	}
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	if decoded.Version != LedgerVersion {
+		return fmt.Errorf("Unsupported ledger version %v", decoded.Version)
+	}
+
+	ledger := NewLedger()
+	ledger.Burned, ledger.Genesis, ledger.Minted = decoded.Burned, decoded.Genesis, decoded.Minted
+
+	for iban, account := range decoded.Accounts {
+		if (account == nil) || (account.IBAN.String() != iban) {
+			return fmt.Errorf("Account %v does not match its IBAN", iban)
+		}
+
+		// Blocks of multisig accounts are verified against their Policy so it must be the one their IBAN is derived from.
+		if account.Multisig() {
+			if err := account.Policy.Validate(); err != nil {
+				return fmt.Errorf("Policy of account %v: %v", iban, err)
+			}
+
+			if address, err := account.IBAN.Address(); (err != nil) || (address != account.Policy.Address()) {
+				return fmt.Errorf("Policy of account %v does not match its IBAN", iban)
+			}
+		}
+
+		if account.Delegates == nil {
+			account.Delegates = make(map[IBAN]bool)
+		}
+
+		ledger.Accounts[iban] = account
+	}
+
+	for iban, blocks := range decoded.Blocks {
+		account, exist := ledger.Accounts[iban]
+
+		if !exist {
+			return fmt.Errorf("Blocks of unknown account %v", iban)
+		}
+
+		for i, block := range blocks {
+			if err := ledger.AppendBlock(block, account.IBAN); err != nil {
+				return fmt.Errorf("Block %v of %v: %v", i, iban, err)
+			}
+		}
+	}
+
+	for iban, account := range ledger.Accounts {
+		if account.Multisig() {
+			continue
+		}
+
+		authorized, err := ledger.AuthorizedKey(account.IBAN)
+
+		if err != nil {
+			return fmt.Errorf("Account %v: %v", iban, err)
+		}
+
+		if (account.Key != nil) && !bytes.Equal(account.Key.Verifier().Bytes(), authorized.Bytes()) {
+			return fmt.Errorf("Key of account %v is not the key authorized by its chain", iban)
+		}
+	}
+
+	for iban, receivables := range decoded.Pending {
+		if _, exist := ledger.Accounts[iban]; !exist {
+			return fmt.Errorf("Pending amounts of unknown account %v", iban)
+		}
+
+		ledger.Pending[iban] = receivables
+	}
+
+	if decoded.Users != nil {
+		for iban, username := range decoded.Users.Usernames {
+			account, exist := ledger.Accounts[iban]
+
+			if !exist {
+				return fmt.Errorf("Username %v of unknown account %v", username, iban)
+			}
+
+			if err := ledger.Users.Register(username, account.IBAN); err != nil {
+				return err
+			}
+		}
+
+		for username, iban := range decoded.Users.IBANs {
+			if registered, exist := ledger.Users.IBAN(username); !exist || (registered != iban) {
+				return fmt.Errorf("Username %v does not match IBAN %v", username, iban)
+			}
+		}
+	}
+
+	*l = *ledger
+	return nil
+}
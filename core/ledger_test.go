@@ -0,0 +1,502 @@
+package core
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kookehs/watchmen/crypto"
+	"github.com/kookehs/watchmen/primitives"
+)
+
+type testStatus struct{}
+
+func (testStatus) Available(string) bool {
+	return true
+}
+
+// testLedger is a Ledger exercising every kind of account and block.
+type testLedger struct {
+	alice   *Account
+	bob     *Account
+	ledger  *Ledger
+	node    *Node
+	signers []crypto.Signer
+	vault   *Account
+}
+
+// newTestLedger returns a Ledger with P-256, Ed25519 and multisig accounts that
+// have transferred funds, renamed themselves and rotated keys.
+func newTestLedger(t *testing.T) *testLedger {
+	t.Helper()
+	ledger := NewLedger()
+	dpos := NewDPoS()
+	node := NewNode(dpos, ledger, testStatus{})
+	genesis, err := node.OpenGenesisAccount("genesis")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Genesis splits its supply between the genesis delegates so the first funds the accounts.
+	funder := ledger.Accounts[node.OpenGenesisDelegates(genesis)[0].IBAN.String()]
+	alice := mustOpenAccount(t, node, crypto.P256, "alice")
+	bob := mustOpenAccount(t, node, crypto.Ed25519, "bob")
+
+	signers := []crypto.Signer{mustSigner(t, crypto.P256), mustSigner(t, crypto.Ed25519)}
+	policy, err := primitives.NewPolicy(2, []crypto.Verifier{signers[0].Verifier(), signers[1].Verifier()})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vault, err := node.OpenMultisigAccount("vault", policy, signers...)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mustTransfer(t, node, 100, alice, funder)
+	mustTransfer(t, node, 100, bob, funder)
+	mustTransfer(t, node, 50, vault, funder)
+
+	if _, err := node.ReceivePending(vault, signers...); err != nil {
+		t.Fatal(err)
+	}
+
+	mustTransfer(t, node, 10, alice, vault, signers...)
+	mustTransfer(t, node, 5, bob, alice)
+
+	// Rename alice and rotate her key to an Ed25519 key.
+	if _, err := node.ReleaseUsername(alice); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := node.RegisterUsername(alice, "alicia"); err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := primitives.NewSchemeKeyForICAP(crypto.Ed25519, rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := node.RotateKey(alice, key); err != nil {
+		t.Fatal(err)
+	}
+
+	mustTransfer(t, node, 1, bob, alice)
+
+	// Leave an amount pending for the multisig account.
+	mustTransfer(t, node, 2, vault, bob)
+
+	return &testLedger{
+		alice:   alice,
+		bob:     bob,
+		ledger:  ledger,
+		node:    node,
+		signers: signers,
+		vault:   vault,
+	}
+}
+
+func mustOpenAccount(t *testing.T, node *Node, scheme crypto.Scheme, username string) *Account {
+	t.Helper()
+	defer func(scheme crypto.Scheme) {
+		KeyScheme = scheme
+	}(KeyScheme)
+
+	KeyScheme = scheme
+	account, err := node.OpenAccount(username)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return account
+}
+
+func mustSigner(t *testing.T, scheme crypto.Scheme) crypto.Signer {
+	t.Helper()
+	signer, err := crypto.GenerateSigner(scheme, rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer
+}
+
+func mustTransfer(t *testing.T, node *Node, amt float64, dst, src *Account, signers ...crypto.Signer) {
+	t.Helper()
+
+	if _, err := node.Transfer(primitives.NewAmount(amt), dst.IBAN, src.IBAN, signers...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkSameLedger reports differences between the chains, balances and usernames of two ledgers.
+func checkSameLedger(t *testing.T, got, want *Ledger) {
+	t.Helper()
+
+	if len(got.Accounts) != len(want.Accounts) {
+		t.Errorf("Imported %v accounts, want %v", len(got.Accounts), len(want.Accounts))
+	}
+
+	for iban, blocks := range want.Blocks {
+		imported := got.Blocks[iban]
+
+		if len(imported) != len(blocks) {
+			t.Errorf("Chain of %v has %v blocks, want %v", iban, len(imported), len(blocks))
+			continue
+		}
+
+		for i := range blocks {
+			gotHash, err := imported[i].Hash()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantHash, err := blocks[i].Hash()
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if gotHash != wantHash {
+				t.Errorf("Block %v of %v = %v, want %v", i, iban, gotHash, wantHash)
+			}
+		}
+
+		account := want.Accounts[iban]
+		gotBalance := got.LatestBlock(account.IBAN).Balance()
+		wantBalance := want.LatestBlock(account.IBAN).Balance()
+
+		if gotBalance.Cmp(wantBalance) != 0 {
+			t.Errorf("Balance of %v = %v, want %v", iban, gotBalance, wantBalance)
+		}
+
+		if !account.Multisig() {
+			gotKey, err := got.AuthorizedKey(account.IBAN)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantKey, err := want.AuthorizedKey(account.IBAN)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !bytes.Equal(gotKey.Bytes(), wantKey.Bytes()) {
+				t.Errorf("Authorized key of %v differs", iban)
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(got.Users, want.Users) {
+		t.Errorf("Users = %+v, want %+v", got.Users, want.Users)
+	}
+
+	for _, amounts := range [][2]primitives.Amount{{got.Burned, want.Burned}, {got.Genesis, want.Genesis}, {got.Minted, want.Minted}} {
+		if amounts[0].Cmp(amounts[1]) != 0 {
+			t.Errorf("Ledger amount = %v, want %v", amounts[0], amounts[1])
+		}
+	}
+
+	for iban, pending := range want.Pending {
+		if len(got.Pending[iban]) != len(pending) {
+			t.Errorf("Pending of %v = %v, want %v", iban, len(got.Pending[iban]), len(pending))
+		}
+	}
+}
+
+func TestLedgerJSONRoundTrip(t *testing.T) {
+	test := newTestLedger(t)
+	data, err := json.Marshal(test.ledger)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if bytes.Contains(data, []byte(`"private"`)) {
+		t.Error("MarshalJSON exported private keys")
+	}
+
+	imported := &Ledger{}
+
+	if err := json.Unmarshal(data, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	checkSameLedger(t, imported, test.ledger)
+
+	for iban, account := range imported.Accounts {
+		if account.Key != nil {
+			t.Errorf("Account %v was imported with a private key", iban)
+		}
+	}
+
+	if iban, _ := imported.Users.IBAN("alicia"); iban != test.alice.IBAN {
+		t.Errorf("alicia = %v, want %v", iban.String(), test.alice.IBAN.String())
+	}
+
+	// Accounts imported without private keys cannot sign or witness blocks.
+	node := NewNode(NewDPoS(), imported, testStatus{})
+	_, err = node.Transfer(primitives.NewAmount(1), test.bob.IBAN, test.alice.IBAN)
+
+	if !errors.Is(err, ErrNoPrivateKey) {
+		t.Errorf("Transfer without private key = %v, want %v", err, ErrNoPrivateKey)
+	}
+}
+
+func TestLedgerJSONWithKeysRoundTrip(t *testing.T) {
+	test := newTestLedger(t)
+	data, err := test.ledger.MarshalJSONWithKeys()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported := &Ledger{}
+
+	if err := json.Unmarshal(data, imported); err != nil {
+		t.Fatal(err)
+	}
+
+	checkSameLedger(t, imported, test.ledger)
+
+	for iban, account := range test.ledger.Accounts {
+		key := imported.Accounts[iban].Key
+
+		if (account.Key == nil) != (key == nil) {
+			t.Errorf("Key of %v = %v, want %v", iban, key, account.Key)
+			continue
+		}
+
+		if (key != nil) && ((key.Address != account.Key.Address) || (key.Scheme != account.Key.Scheme)) {
+			t.Errorf("Key of %v = %v %v, want %v %v", iban, key.Scheme, key.Address, account.Key.Scheme, account.Key.Address)
+		}
+	}
+
+	// Imported private keys keep signing for their accounts including the rotated key.
+	node := NewNode(NewDPoS(), imported, testStatus{})
+	alice := imported.Accounts[test.alice.IBAN.String()]
+	bob := imported.Accounts[test.bob.IBAN.String()]
+	mustTransfer(t, node, 1, bob, alice)
+	mustTransfer(t, node, 1, alice, bob)
+
+	// Multisig accounts are signed by their policy keys which are never part of the Ledger.
+	vault := imported.Accounts[test.vault.IBAN.String()]
+
+	if _, err := node.ReceivePending(vault, test.signers...); err != nil {
+		t.Errorf("ReceivePending of imported multisig account: %v", err)
+	}
+}
+
+func TestLedgerJSONRejectsUnauthorizedKey(t *testing.T) {
+	test := newTestLedger(t)
+	alice, bob := test.alice, test.bob
+
+	// Swap the private keys of two accounts.
+	alice.Key, bob.Key = bob.Key, alice.Key
+	data, err := test.ledger.MarshalJSONWithKeys()
+	alice.Key, bob.Key = bob.Key, alice.Key
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(data, &Ledger{}); err == nil {
+		t.Error("Unmarshal with swapped keys succeeded")
+	}
+
+	var fields map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, version := range []string{"0", "2"} {
+		fields["version"] = json.RawMessage(version)
+		tampered, err := json.Marshal(fields)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := json.Unmarshal(tampered, &Ledger{}); err == nil {
+			t.Errorf("Unmarshal of version %v succeeded", version)
+		}
+	}
+}
+
+func TestMultisigAuthorization(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, node, vault, signers := test.ledger, test.node, test.vault, test.signers
+	outsider := mustSigner(t, crypto.Ed25519)
+	prev := ledger.LatestBlock(vault.IBAN)
+	pending := len(ledger.Pending[vault.IBAN.String()])
+
+	tests := []struct {
+		name    string
+		signers []crypto.Signer
+	}{
+		{"threshold not met", signers[:1]},
+		{"duplicate signer", []crypto.Signer{signers[0], signers[0]}},
+		{"non-member signer", []crypto.Signer{signers[0], outsider}},
+	}
+
+	for _, tc := range tests {
+		if _, err := node.Transfer(primitives.NewAmount(1), test.alice.IBAN, vault.IBAN, tc.signers...); err == nil {
+			t.Errorf("Transfer with %v succeeded", tc.name)
+		}
+
+		if _, err := node.ReceivePending(vault, tc.signers...); err == nil {
+			t.Errorf("ReceivePending with %v succeeded", tc.name)
+		}
+	}
+
+	if ledger.LatestBlock(vault.IBAN) != prev {
+		t.Error("Block was appended without enough signers")
+	}
+
+	if len(ledger.Pending[vault.IBAN.String()]) != pending {
+		t.Errorf("Pending = %v, want %v", len(ledger.Pending[vault.IBAN.String()]), pending)
+	}
+
+	policy, err := primitives.NewPolicy(2, []crypto.Verifier{signers[0].Verifier(), outsider.Verifier()})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := node.OpenMultisigAccount("treasury", policy, signers[0]); err == nil {
+		t.Error("OpenMultisigAccount with threshold not met succeeded")
+	}
+
+	if _, exist := ledger.Accounts[NewMultisigAccount(policy).IBAN.String()]; exist {
+		t.Error("Account of rejected OpenMultisigAccount remains in the ledger")
+	}
+
+	// OpenBlocks must declare the policy the account is derived from.
+	fresh := NewLedger()
+	account := NewMultisigAccount(policy)
+	fresh.Accounts[account.IBAN.String()] = account
+	foreign := primitives.NewMultisigOpenBlock(primitives.NewAmount(0), account.IBAN, vault.Policy)
+	open := primitives.NewMultisigOpenBlock(primitives.NewAmount(0), account.IBAN, policy)
+
+	for _, signer := range append(signers, outsider) {
+		if err := foreign.Cosign(signer); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := open.Cosign(signer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := fresh.AppendBlock(foreign, account.IBAN); err == nil {
+		t.Error("AppendBlock of OpenBlock declaring another policy succeeded")
+	}
+
+	if err := fresh.AppendBlock(open, account.IBAN); err != nil {
+		t.Errorf("AppendBlock of OpenBlock declaring the policy: %v", err)
+	}
+}
+
+// tamperAccount returns the given ledger JSON with a field of the account with the given IBAN replaced.
+func tamperAccount(t *testing.T, data []byte, iban primitives.IBAN, field string, value interface{}) []byte {
+	t.Helper()
+	var fields map[string]json.RawMessage
+	var accounts map[string]map[string]json.RawMessage
+
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(fields["accounts"], &accounts); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(value)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accounts[iban.String()][field] = b
+
+	if fields["accounts"], err = json.Marshal(accounts); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered, err := json.Marshal(fields)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tampered
+}
+
+func TestLedgerJSONRejectsForeignPolicy(t *testing.T) {
+	test := newTestLedger(t)
+	data, err := json.Marshal(test.ledger)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outsider := mustSigner(t, crypto.Ed25519)
+	policy, err := primitives.NewPolicy(1, []crypto.Verifier{outsider.Verifier()})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		account *Account
+		policy  *primitives.Policy
+	}{
+		{"policy of other keys", test.vault, policy},
+		{"policy of another account", test.alice, test.vault.Policy},
+		{"missing policy", test.vault, nil},
+	}
+
+	for _, tc := range tests {
+		tampered := tamperAccount(t, data, tc.account.IBAN, "policy", tc.policy)
+
+		if err := json.Unmarshal(tampered, &Ledger{}); err == nil {
+			t.Errorf("Unmarshal with %v succeeded", tc.name)
+		}
+	}
+
+	// A chain signed by the keys of a foreign policy claims the IBAN of the multisig account.
+	forged := NewLedger()
+	account := NewMultisigAccount(policy)
+	account.BBAN, account.IBAN = test.vault.BBAN, test.vault.IBAN
+	open := primitives.NewMultisigOpenBlock(primitives.NewAmount(1000), account.IBAN, policy)
+
+	if err := open.Cosign(outsider); err != nil {
+		t.Fatal(err)
+	}
+
+	forged.Accounts[account.IBAN.String()] = account
+	forged.Blocks[account.IBAN.String()] = primitives.Blocks{open}
+
+	if data, err = json.Marshal(forged); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := json.Unmarshal(data, &Ledger{}); err == nil {
+		t.Error("Unmarshal of chain signed by a foreign policy succeeded")
+	}
+}
@@ -1,17 +1,41 @@
 package core
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+	"sync/atomic"
 
+	"github.com/kookehs/watchmen/crypto"
 	"github.com/kookehs/watchmen/primitives"
 )
 
 // TODO: Implement network.
 
-// Defines fees for the system
+// Defines fees and payouts for the system
 var (
 	// Fees
 	TransactionFee primitives.Amount = primitives.NewAmount(0.1)
+	// Fraction of fees burned rather than rewarded to the forger
+	BurnFraction float64 = 0.5
+	// Scale TransactionFee once the number of pending requests exceeds FeeTargetLoad
+	DynamicFees   bool = false
+	FeeTargetLoad int  = 8
+
+	// Payouts are split evenly unless ProportionalPayout is chosen
+	PayoutDistribution PayoutMode = EqualPayout
+)
+
+// PayoutMode determines how the shared portion of a reward is split between stakeholders.
+type PayoutMode uint8
+
+// Various payout modes
+const (
+	// EqualPayout splits the shared portion evenly between stakeholders.
+	EqualPayout PayoutMode = iota
+	// ProportionalPayout splits the shared portion by each stakeholder's contribution to the delegate's weight.
+	ProportionalPayout
 )
 
 // Status interface contains functions related to the statuses of a delegate.
@@ -21,27 +45,206 @@ type Status interface {
 
 // Node is the structure responsible for carrying out actions on the network.
 type Node struct {
-	DPoS   *DPoS
+	DPoS *DPoS
+	// Events emitted while processing requests
+	Events *EventBus
 	Ledger *Ledger
 	Status Status
+	// Serializes requests so that they are processed one at a time.
+	// Readers of the DPoS and Ledger hold the read lock while a request is not being processed.
+	mutex sync.RWMutex
+	// Number of requests waiting for or being processed
+	pending atomic.Int64
 }
 
 // NewNode returns a pointer to an initialized Node.
 func NewNode(dpos *DPoS, ledger *Ledger, status Status) *Node {
 	return &Node{
 		DPoS:   dpos,
+		Events: NewEventBus(),
 		Ledger: ledger,
 		Status: status,
 	}
 }
 
-// Process processes the given request taking necessary actions.
+// Fee returns the current transaction fee.
+// When DynamicFees is enabled the fee grows with the number of pending requests above FeeTargetLoad.
+func (n *Node) Fee() primitives.Amount {
+	fee := primitives.NewAmount(0)
+	fee.Copy(TransactionFee)
+	pending := n.Pending()
+
+	if !DynamicFees || (FeeTargetLoad <= 0) || (pending <= FeeTargetLoad) {
+		return fee
+	}
+
+	load := primitives.NewAmount(float64(pending) / float64(FeeTargetLoad))
+	fee.Mul(fee, load)
+	return fee
+}
+
+// Pending returns the number of requests waiting for or being processed.
+func (n *Node) Pending() int {
+	return int(n.pending.Load())
+}
+
+// RLock locks the DPoS and Ledger of the Node for reading.
+// Requests are not processed until every reader calls RUnlock.
+func (n *Node) RLock() {
+	n.mutex.RLock()
+}
+
+// RUnlock undoes a single RLock call.
+func (n *Node) RUnlock() {
+	n.mutex.RUnlock()
+}
+
+// Process queues the given request behind the requests being processed and then processes it.
+// Requests paying less than the current fee are rejected before they are queued.
 func (n *Node) Process(request *Request) (primitives.Block, error) {
+	if err := CheckFee(request.Blueprint, n.Fee()); err != nil {
+		return nil, err
+	}
+
+	unlock := n.lock()
+	defer unlock()
+	return n.process(request)
+}
+
+// lock queues the caller behind the requests being processed and locks the DPoS and Ledger for writing.
+// The returned function undoes the lock.
+func (n *Node) lock() func() {
+	n.pending.Add(1)
+	n.mutex.Lock()
+
+	return func() {
+		n.mutex.Unlock()
+		n.pending.Add(-1)
+	}
+}
+
+// OpenGenesisAccount creates the genesis account of the Ledger.
+// This method is meant to be called once to initialize the system.
+func (n *Node) OpenGenesisAccount(username string) (*Account, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.openGenesisAccount(username)
+}
+
+// OpenGenesisDelegates creates the initial delegates funded by the given genesis account.
+func (n *Node) OpenGenesisDelegates(genesis *Account) []*Account {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.openGenesisDelegates(n.DPoS, genesis, n)
+}
+
+// OpenAccount creates an Account for the given username.
+func (n *Node) OpenAccount(username string) (*Account, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.openAccount(n, username)
+}
+
+// OpenMultisigAccount creates an Account for the given username controlled by the given Policy.
+func (n *Node) OpenMultisigAccount(username string, policy *primitives.Policy, signers ...crypto.Signer) (*Account, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.openMultisigAccount(n, username, policy, signers)
+}
+
+// Transfer sends the given amount from src to dst paying the current transaction fee.
+// Signers are only required when src is a multisig account.
+func (n *Node) Transfer(amt primitives.Amount, dst, src primitives.IBAN, signers ...crypto.Signer) (primitives.Block, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.transfer(amt, dst, src, n, signers)
+}
+
+// ReceivePending receives every amount waiting for the given multisig Account with the given signers.
+func (n *Node) ReceivePending(account *Account, signers ...crypto.Signer) ([]primitives.Block, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.receivePending(account, n, signers)
+}
+
+// RegisterUsername registers the given username to the given Account.
+func (n *Node) RegisterUsername(account *Account, username string, signers ...crypto.Signer) (primitives.Block, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.registerUsername(account, username, n, signers)
+}
+
+// ReleaseUsername releases the username owned by the given Account.
+func (n *Node) ReleaseUsername(account *Account, signers ...crypto.Signer) (primitives.Block, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.releaseUsername(account, n, signers)
+}
+
+// TransferUsername transfers the username owned by the given Account to dst.
+func (n *Node) TransferUsername(account *Account, dst primitives.IBAN, signers ...crypto.Signer) (primitives.Block, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.transferUsername(account, dst, n, signers)
+}
+
+// RotateKey replaces the key of the given Account with key.
+func (n *Node) RotateKey(account *Account, key *primitives.Key) (primitives.Block, error) {
+	unlock := n.lock()
+	defer unlock()
+	return n.Ledger.rotateKey(account, key, n)
+}
+
+// Elect updates the delegates voted for by the given Account.
+// Signers are only required for multisig accounts.
+func (n *Node) Elect(account *Account, delegates []string, signers ...crypto.Signer) error {
+	unlock := n.lock()
+	defer unlock()
+	return n.DPoS.elect(account, delegates, n.Ledger, n, signers)
+}
+
+// process processes the given request taking necessary actions.
+// Blocks created as a consequence of the request are processed without queueing.
+func (n *Node) process(request *Request) (primitives.Block, error) {
 	var forger *Delegate
+	var err error
+
+	if request.Account.Multisig() && (len(request.Signers) < request.Account.Policy.Threshold) {
+		return nil, errors.New("Not enough signers for multisig account")
+	}
+
+	// Sources are validated against the ledger so that sends cannot be received twice.
+	if (request.Blueprint.Type == primitives.Receive) && (request.Blueprint.Source != nil) {
+		if err := n.Ledger.ValidateReceive(request.Account.IBAN, request.Blueprint.Source); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sends are only forged for destinations that can receive them.
+	if request.Blueprint.Type == primitives.Send {
+		if _, exist := n.Ledger.Accounts[request.Blueprint.Destination.String()]; !exist {
+			return nil, fmt.Errorf("Account for %v does not exist", request.Blueprint.Destination.String())
+		}
+	}
+
+	// Names are checked against the registry so that a NameBlock is only appended if it can be applied.
+	if request.Blueprint.Type == primitives.Name {
+		blueprint := request.Blueprint
+
+		if err := n.Ledger.Users.Check(blueprint.Action, blueprint.Name, blueprint.Destination, request.Account.IBAN); err != nil {
+			return nil, err
+		}
+	}
 
 	for {
+		rounds := n.DPoS.Rounds
 		n.DPoS.Update(n.Ledger)
-		forger, err := n.DPoS.Round.Forger()
+
+		if n.DPoS.Rounds != rounds {
+			n.publish(Event{Type: RoundStarted})
+		}
+
+		forger, err = n.DPoS.Round.Forger()
 
 		if err != nil {
 			return nil, err
@@ -53,6 +256,7 @@ func (n *Node) Process(request *Request) (primitives.Block, error) {
 
 		forger.Account.Missed++
 		n.DPoS.Round.Index++
+		n.publish(Event{IBAN: forger.Account.IBAN.String(), Type: ForgerMissed})
 	}
 
 	account := request.Account
@@ -65,7 +269,7 @@ func (n *Node) Process(request *Request) (primitives.Block, error) {
 
 	forger.Account.Forged++
 
-	if err := block.Sign(account.Key.PrivateKey); err != nil {
+	if err := request.Sign(block); err != nil {
 		return nil, err
 	}
 
@@ -73,53 +277,123 @@ func (n *Node) Process(request *Request) (primitives.Block, error) {
 		return nil, err
 	}
 
+	n.publish(Event{Amount: blueprint.Amount, Block: block, IBAN: account.IBAN.String(), Type: BlockAppended})
+
 	reward := primitives.NewAmount(0)
+	minted := n.DPoS.Reward()
 
 	switch blueprint.Type {
 	case primitives.Change:
-		reward.Copy(ForgeReward)
-		reward.Add(reward, VotingFee)
-		reward.Add(reward, TransactionFee)
+		n.publish(Event{Delegates: blueprint.Delegates, IBAN: account.IBAN.String(), Type: VoteChanged})
+		reward.Copy(minted)
+		reward.Add(reward, n.Collect(blueprint.Fee))
 	case primitives.Delegate:
 		account.Delegate = true
 		account.Share = blueprint.Share
 		n.DPoS.Delegates = append(n.DPoS.Delegates, NewDelegate(account))
-		reward.Copy(ForgeReward)
-		reward.Add(reward, DelegateFee)
-		reward.Add(reward, TransactionFee)
+		n.publish(Event{IBAN: account.IBAN.String(), Type: DelegateRegistered})
+		reward.Copy(minted)
+		reward.Add(reward, n.Collect(blueprint.Fee))
+	case primitives.Name:
+		if err := n.Ledger.Users.Apply(blueprint.Action, blueprint.Name, blueprint.Destination, account.IBAN); err != nil {
+			return nil, err
+		}
+
+		// No reward is minted for forging a NameBlock.
+		minted = primitives.NewAmount(0)
+		reward.Add(reward, n.Collect(blueprint.Fee))
 	case primitives.Open:
-		reward.Copy(ForgeReward)
+		reward.Copy(minted)
 	case primitives.Receive:
 		// No reward for forging a ReceiveBlock.
+	case primitives.Rotate:
+		// No reward for forging a RotateBlock.
+		account.Key = blueprint.Key
 	case primitives.Send:
 		destination := n.Ledger.Accounts[blueprint.Destination.String()]
-		prev := n.Ledger.LatestBlock(destination.IBAN)
-		blueprint, err := destination.CreateReceiveBlock(blueprint.Amount, &account.Key.PrivateKey.PublicKey, prev, block)
 
-		if err != nil {
-			return nil, err
-		}
+		// Multisig accounts receive once enough keys of their policy sign.
+		if destination.Multisig() {
+			n.Ledger.AddPending(destination.IBAN, blueprint.Amount, block)
+		} else {
+			prev := n.Ledger.LatestBlock(destination.IBAN)
+			receive, err := destination.CreateReceiveBlock(blueprint.Amount, account, prev, block)
 
-		_, err = n.Process(NewRequest(destination, blueprint))
+			if err != nil {
+				return nil, err
+			}
 
-		if err != nil {
-			return nil, err
+			_, err = n.process(NewRequest(destination, receive))
+
+			if err != nil {
+				return nil, err
+			}
 		}
 
-		reward.Copy(ForgeReward)
-		reward.Add(reward, TransactionFee)
+		reward.Copy(minted)
+		reward.Add(reward, n.Collect(blueprint.Fee))
 	default:
 		log.Println("Invalid block type")
 	}
 
 	if reward.Cmp(primitives.NewAmount(0)) == 1 {
+		n.Ledger.Mint(minted)
 		n.Payout(forger.Account, reward)
 	}
 
+	// Pay out rewards settled when the round rolled over.
+	if len(n.DPoS.Settlements) > 0 {
+		n.Distribute(forger)
+	}
+
 	return block, nil
 }
 
-// Payout distributes the block reward to stakeholders evenly according to share.
+// CheckFee returns an error if the given blueprint pays less than the given transaction fee.
+// ChangeBlocks and DelegateBlocks must also pay VotingFee and DelegateFee.
+// The username registered by the block following an OpenBlock is free as the account has no funds yet.
+func CheckFee(blueprint *Blueprint, fee primitives.Amount) error {
+	required := primitives.NewAmount(0)
+	required.Copy(fee)
+
+	switch blueprint.Type {
+	case primitives.Change:
+		required.Add(required, VotingFee)
+	case primitives.Delegate:
+		required.Add(required, DelegateFee)
+	case primitives.Name:
+		if (blueprint.Action == primitives.RegisterName) && (blueprint.Previous != nil) && (blueprint.Previous.Type() == primitives.Open) {
+			return nil
+		}
+	case primitives.Send:
+	default:
+		return nil
+	}
+
+	if (blueprint.Fee == nil) || (blueprint.Fee.Cmp(required) == -1) {
+		return fmt.Errorf("Fee of %v is below the required fee of %v", blueprint.Fee, required)
+	}
+
+	return nil
+}
+
+// Collect burns BurnFraction of the given fee and returns the remainder for the forger.
+func (n *Node) Collect(fee primitives.Amount) primitives.Amount {
+	collected := primitives.NewAmount(0)
+
+	if fee == nil {
+		return collected
+	}
+
+	burn := primitives.NewAmount(BurnFraction)
+	burn.Mul(burn, fee)
+	n.Ledger.Burn(burn)
+	collected.Sub(fee, burn)
+	return collected
+}
+
+// Payout accrues the block reward to stakeholders according to share and PayoutDistribution.
+// Accrued rewards are paid out by Distribute once the round has been settled.
 func (n *Node) Payout(forger *Account, reward primitives.Amount) {
 	stakeholders := n.Ledger.Stakeholders(forger.IBAN)
 
@@ -128,55 +402,153 @@ func (n *Node) Payout(forger *Account, reward primitives.Amount) {
 	share.Quo(share, primitives.NewAmount(100))
 	share.Mul(reward, share)
 
+	// Forger keeps the entire reward when there is no one to share with.
+	if len(stakeholders) == 0 {
+		share.SetFloat64(0)
+	}
+
 	// Calculate the amount to be kept by forger.
 	keep := primitives.NewAmount(0)
 	keep.Sub(reward, share)
 
 	if share.Cmp(primitives.NewAmount(0)) == 1 {
 		// Calculate the split.
-		ways := primitives.NewAmount(float64(len(stakeholders)))
-		share.Quo(share, ways)
+		splits := n.Split(share, stakeholders)
 
 		for _, stakeholder := range stakeholders {
-			prev := n.Ledger.LatestBlock(stakeholder.IBAN)
-			blueprint, err := stakeholder.CreateReceiveBlock(share, nil, prev, nil)
+			amount := splits[stakeholder.IBAN.String()]
 
-			if err != nil {
-				log.Println(err)
+			if amount.Cmp(primitives.NewAmount(0)) != 1 {
 				continue
 			}
 
-			_, err = n.Process(NewRequest(stakeholder, blueprint))
-
-			if err != nil {
-				log.Println(err)
-				continue
-			}
+			n.DPoS.Accrue(stakeholder.IBAN, amount)
 		}
 	}
 
 	if keep.Cmp(primitives.NewAmount(0)) == 1 {
-		prev := n.Ledger.LatestBlock(forger.IBAN)
-		blueprint, err := forger.CreateReceiveBlock(keep, nil, prev, nil)
+		n.DPoS.Accrue(forger.IBAN, keep)
+	}
+}
 
-		if err != nil {
-			log.Println(err)
-			return
+// Distribute pays out settled rewards with a single ReceiveBlock per account.
+// Payouts are witnessed by the given forger without taking forging slots so that
+// rounds do not depend on the number of stakeholders.
+// Rewards that fail to be paid out remain settled for the next distribution.
+func (n *Node) Distribute(forger *Delegate) {
+	settlements := n.DPoS.Settlements
+	n.DPoS.Settlements = make(map[IBAN]primitives.Amount)
+
+	for iban, amount := range settlements {
+		account, exist := n.Ledger.Accounts[iban]
+
+		if !exist {
+			log.Printf("Unable to pay out rewards to unknown account %v\n", iban)
+			continue
 		}
 
-		_, err = n.Process(NewRequest(forger, blueprint))
+		if account.Multisig() {
+			n.Ledger.AddPending(account.IBAN, amount, nil)
+			continue
+		}
 
-		if err != nil {
+		if err := n.payout(forger, account, amount); err != nil {
 			log.Println(err)
-			return
+			AddAmount(n.DPoS.Settlements, iban, amount)
+			continue
 		}
 	}
 }
 
+// payout appends a ReceiveBlock of the given amount to the account witnessed by the given forger.
+func (n *Node) payout(forger *Delegate, account *Account, amt primitives.Amount) error {
+	prev := n.Ledger.LatestBlock(account.IBAN)
+	blueprint, err := account.CreateReceiveBlock(amt, nil, prev, nil)
+
+	if err != nil {
+		return err
+	}
+
+	block, err := forger.Forge(account, blueprint)
+
+	if err != nil {
+		return err
+	}
+
+	if err := NewRequest(account, blueprint).Sign(block); err != nil {
+		return err
+	}
+
+	if err := n.Ledger.AppendBlock(block, account.IBAN); err != nil {
+		return err
+	}
+
+	n.publish(Event{Amount: blueprint.Amount, Block: block, IBAN: account.IBAN.String(), Type: BlockAppended})
+	return nil
+}
+
+// publish emits the given event for the current round if the Node has an EventBus.
+func (n *Node) publish(event Event) {
+	if n.Events == nil {
+		return
+	}
+
+	event.Round = n.DPoS.Rounds
+	n.Events.Publish(event)
+}
+
+// Split divides the given amount between stakeholders according to PayoutDistribution.
+// Proportional splits weigh each stakeholder by their balance as done in CalculateWeights.
+// If stakeholders hold no balance the amount is split evenly instead.
+func (n *Node) Split(amt primitives.Amount, stakeholders []*Account) map[IBAN]primitives.Amount {
+	splits := make(map[IBAN]primitives.Amount)
+
+	if len(stakeholders) == 0 {
+		return splits
+	}
+
+	weights := make(map[IBAN]primitives.Amount)
+	total := primitives.NewAmount(0)
+
+	for _, stakeholder := range stakeholders {
+		weight := primitives.NewAmount(0)
+
+		if prev := n.Ledger.LatestBlock(stakeholder.IBAN); prev != nil {
+			weight.Copy(prev.Balance())
+		}
+
+		weights[stakeholder.IBAN.String()] = weight
+		total.Add(total, weight)
+	}
+
+	if (PayoutDistribution == EqualPayout) || (total.Cmp(primitives.NewAmount(0)) != 1) {
+		ways := primitives.NewAmount(float64(len(stakeholders)))
+
+		for _, stakeholder := range stakeholders {
+			split := primitives.NewAmount(0)
+			split.Quo(amt, ways)
+			splits[stakeholder.IBAN.String()] = split
+		}
+
+		return splits
+	}
+
+	for iban, weight := range weights {
+		split := primitives.NewAmount(0)
+		split.Mul(amt, weight)
+		split.Quo(split, total)
+		splits[iban] = split
+	}
+
+	return splits
+}
+
 // Request is an instruction for a Node.
 type Request struct {
 	Account   *Account
 	Blueprint *Blueprint
+	// Keys of the policy signing for a multisig Account
+	Signers []crypto.Signer
 }
 
 // NewRequest returns a pointer to an initialized Request.
@@ -184,5 +556,42 @@ func NewRequest(account *Account, blueprint *Blueprint) *Request {
 	return &Request{
 		Account:   account,
 		Blueprint: blueprint,
+		Signers:   nil,
+	}
+}
+
+// NewMultisigRequest returns a pointer to an initialized Request signed by the given keys.
+func NewMultisigRequest(account *Account, blueprint *Blueprint, signers []crypto.Signer) *Request {
+	return &Request{
+		Account:   account,
+		Blueprint: blueprint,
+		Signers:   signers,
+	}
+}
+
+// Sign signs the given block on behalf of the Account.
+// Blocks of multisig accounts are cosigned by Signers and must satisfy the policy.
+// RotateBlocks are signed by the key being rotated to.
+func (r *Request) Sign(block primitives.Block) error {
+	if block.Type() == primitives.Rotate {
+		return block.Sign(r.Blueprint.Key.Signer())
 	}
+
+	if !r.Account.Multisig() {
+		signer, err := r.Account.Signer()
+
+		if err != nil {
+			return err
+		}
+
+		return block.Sign(signer)
+	}
+
+	for _, signer := range r.Signers {
+		if err := block.Cosign(signer); err != nil {
+			return err
+		}
+	}
+
+	return VerifyPolicyBlock(block, r.Account.Policy)
 }
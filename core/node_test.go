@@ -0,0 +1,140 @@
+package core
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/kookehs/watchmen/crypto"
+	"github.com/kookehs/watchmen/primitives"
+)
+
+// newStakeholder returns an Account of the given Ledger whose chain is an OpenBlock with the given balance.
+func newStakeholder(t *testing.T, ledger *Ledger, balance float64) *Account {
+	t.Helper()
+	key, err := primitives.NewSchemeKeyForICAP(crypto.Ed25519, rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	account := NewAccount(key)
+	pub := primitives.MakePublicKey(key.Verifier())
+	open := primitives.NewOpenBlock(primitives.NewAmount(balance), account.IBAN, &pub)
+	ledger.Accounts[account.IBAN.String()] = account
+	ledger.Blocks[account.IBAN.String()] = primitives.Blocks{open}
+	return account
+}
+
+func TestSplit(t *testing.T) {
+	defer func(mode PayoutMode) {
+		PayoutDistribution = mode
+	}(PayoutDistribution)
+
+	tests := []struct {
+		mode     PayoutMode
+		balances []float64
+		splits   []float64
+	}{
+		{EqualPayout, []float64{10, 30, 60}, []float64{20, 20, 20}},
+		{ProportionalPayout, []float64{10, 30, 20}, []float64{10, 30, 20}},
+		{ProportionalPayout, []float64{0, 45, 15}, []float64{0, 45, 15}},
+		// Stakeholders without any balance split evenly.
+		{ProportionalPayout, []float64{0, 0}, []float64{30, 30}},
+		{ProportionalPayout, nil, nil},
+	}
+
+	for _, test := range tests {
+		PayoutDistribution = test.mode
+		node := NewNode(NewDPoS(), NewLedger(), testStatus{})
+		stakeholders := make([]*Account, 0, len(test.balances))
+
+		for _, balance := range test.balances {
+			stakeholders = append(stakeholders, newStakeholder(t, node.Ledger, balance))
+		}
+
+		splits := node.Split(primitives.NewAmount(60), stakeholders)
+
+		if len(splits) != len(stakeholders) {
+			t.Errorf("Split %v between %v returned %v splits", test.mode, test.balances, len(splits))
+			continue
+		}
+
+		for i, stakeholder := range stakeholders {
+			split := splits[stakeholder.IBAN.String()]
+
+			if split.Cmp(primitives.NewAmount(test.splits[i])) != 0 {
+				t.Errorf("Split %v between %v: stakeholder %v = %v, want %v", test.mode, test.balances, i, split, test.splits[i])
+			}
+		}
+	}
+}
+
+func TestDefaultPayoutDistribution(t *testing.T) {
+	if PayoutDistribution != EqualPayout {
+		t.Errorf("PayoutDistribution = %v, want EqualPayout", PayoutDistribution)
+	}
+}
+
+func TestProcessRejectsLowFee(t *testing.T) {
+	test := newTestLedger(t)
+	ledger, node, alice, bob := test.ledger, test.node, test.alice, test.bob
+	prev := ledger.LatestBlock(bob.IBAN)
+	zero := primitives.NewAmount(0)
+
+	send, err := bob.CreateSendBlock(primitives.NewAmount(1), zero, alice.IBAN, prev)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only VotingFee and DelegateFee are paid without the transaction fee.
+	change, err := bob.CreateChangeBlock([]primitives.IBAN{alice.IBAN}, zero, prev)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	delegate, err := bob.CreateDelegateBlock(zero, prev, 50)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name, err := bob.CreateNameBlock(primitives.ReleaseName, "bob", primitives.IBAN{}, zero, prev)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, blueprint := range []*Blueprint{send, change, delegate, name} {
+		if _, err := node.Process(NewRequest(bob, blueprint)); err == nil {
+			t.Errorf("Process of %v without a fee succeeded", blueprint.Type)
+		}
+	}
+
+	if latest := ledger.LatestBlock(bob.IBAN); latest != prev {
+		t.Errorf("Block %v was appended without a fee", latest.Type())
+	}
+
+	defer func(dynamic bool, load int) {
+		DynamicFees, FeeTargetLoad = dynamic, load
+	}(DynamicFees, FeeTargetLoad)
+
+	// Requests built before the load increased pay less than the scaled fee.
+	DynamicFees, FeeTargetLoad = true, 1
+	node.pending.Add(3)
+	defer node.pending.Add(-3)
+	send, err = bob.CreateSendBlock(primitives.NewAmount(1), TransactionFee, alice.IBAN, prev)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := node.Process(NewRequest(bob, send)); err == nil {
+		t.Error("Process of SendBlock paying less than the dynamic fee succeeded")
+	}
+
+	if _, err := node.Transfer(primitives.NewAmount(1), alice.IBAN, bob.IBAN); err != nil {
+		t.Errorf("Transfer paying the dynamic fee: %v", err)
+	}
+}
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/kookehs/watchmen/primitives"
+)
+
+// Defines rules for usernames
+var (
+	// Limits
+	MaxUsernameLength int = 20
+	MinUsernameLength int = 3
+
+	// Usernames that cannot be registered by accounts
+	ReservedUsernames = map[Username]bool{
+		"admin":         true,
+		"administrator": true,
+		"delegate":      true,
+		"root":          true,
+		"system":        true,
+		"watchmen":      true,
+	}
+)
+
+// ValidateUsername returns whether or not the given username follows the rules for usernames.
+// Usernames must be lowercase and only contain letters, digits and underscores.
+func ValidateUsername(username Username) error {
+	if (len(username) < MinUsernameLength) || (len(username) > MaxUsernameLength) {
+		return fmt.Errorf("Length of username must be between %v and %v", MinUsernameLength, MaxUsernameLength)
+	}
+
+	for _, c := range username {
+		if !((c >= 'a') && (c <= 'z')) && !((c >= '0') && (c <= '9')) && (c != '_') {
+			return fmt.Errorf("Invalid character in username: %q", c)
+		}
+	}
+
+	if ReservedUsernames[username] {
+		return fmt.Errorf("Username %v is reserved", username)
+	}
+
+	return nil
+}
+
+// Registry is a bidirectional index between usernames and the IBANs that own them.
+type Registry struct {
+	IBANs     map[Username]primitives.IBAN `json:"ibans"`
+	Usernames map[IBAN]Username            `json:"usernames"`
+}
+
+// NewRegistry returns a pointer to an initialized Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		IBANs:     make(map[Username]primitives.IBAN),
+		Usernames: make(map[IBAN]Username),
+	}
+}
+
+// Apply performs the given action on the registry.
+func (r *Registry) Apply(action primitives.NameAction, username Username, dst, src primitives.IBAN) error {
+	switch action {
+	case primitives.RegisterName:
+		return r.Register(username, src)
+	case primitives.ReleaseName:
+		return r.Release(username, src)
+	case primitives.TransferName:
+		return r.Transfer(username, dst, src)
+	default:
+		return errors.New("Invalid name action")
+	}
+}
+
+// Check returns whether or not the given action can be performed on the registry.
+func (r *Registry) Check(action primitives.NameAction, username Username, dst, src primitives.IBAN) error {
+	switch action {
+	case primitives.RegisterName:
+		if err := ValidateUsername(username); err != nil {
+			return err
+		}
+
+		if _, exist := r.IBANs[username]; exist {
+			return fmt.Errorf("Username %v is already registered", username)
+		}
+
+		if _, exist := r.Usernames[src.String()]; exist {
+			return errors.New("Account already has a username")
+		}
+	case primitives.ReleaseName:
+		if owner, exist := r.IBANs[username]; !exist || (owner != src) {
+			return fmt.Errorf("Username %v is not owned by the account", username)
+		}
+	case primitives.TransferName:
+		if owner, exist := r.IBANs[username]; !exist || (owner != src) {
+			return fmt.Errorf("Username %v is not owned by the account", username)
+		}
+
+		if _, exist := r.Usernames[dst.String()]; exist {
+			return errors.New("Destination already has a username")
+		}
+	default:
+		return errors.New("Invalid name action")
+	}
+
+	return nil
+}
+
+// IBAN returns the IBAN that owns the given username.
+func (r *Registry) IBAN(username Username) (primitives.IBAN, bool) {
+	iban, exist := r.IBANs[username]
+	return iban, exist
+}
+
+// Register assigns the given username to the given IBAN.
+func (r *Registry) Register(username Username, iban primitives.IBAN) error {
+	if err := r.Check(primitives.RegisterName, username, primitives.IBAN{}, iban); err != nil {
+		return err
+	}
+
+	r.IBANs[username] = iban
+	r.Usernames[iban.String()] = username
+	return nil
+}
+
+// Release removes the given username from the given IBAN making it available again.
+func (r *Registry) Release(username Username, iban primitives.IBAN) error {
+	if err := r.Check(primitives.ReleaseName, username, primitives.IBAN{}, iban); err != nil {
+		return err
+	}
+
+	delete(r.IBANs, username)
+	delete(r.Usernames, iban.String())
+	return nil
+}
+
+// Transfer moves the given username from src to dst.
+func (r *Registry) Transfer(username Username, dst, src primitives.IBAN) error {
+	if err := r.Check(primitives.TransferName, username, dst, src); err != nil {
+		return err
+	}
+
+	delete(r.Usernames, src.String())
+	r.IBANs[username] = dst
+	r.Usernames[dst.String()] = username
+	return nil
+}
+
+// Username returns the username owned by the given IBAN.
+func (r *Registry) Username(iban primitives.IBAN) (Username, bool) {
+	username, exist := r.Usernames[iban.String()]
+	return username, exist
+}
+
+// Deserialize decodes byte data encoded by gob.
+func (r *Registry) Deserialize(rd io.Reader) error {
+	decoder := gob.NewDecoder(rd)
+	return decoder.Decode(r)
+}
+
+// DeserializeJSON decodes JSON data.
+func (r *Registry) DeserializeJSON(rd io.Reader) error {
+	decoder := json.NewDecoder(rd)
+	return decoder.Decode(r)
+}
+
+// Serialize encodes to byte data using gob.
+func (r *Registry) Serialize(w io.Writer) error {
+	encoder := gob.NewEncoder(w)
+	return encoder.Encode(r)
+}
+
+// SerializeJSON encodes to JSON data.
+func (r *Registry) SerializeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(r)
+}
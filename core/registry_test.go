@@ -0,0 +1,114 @@
+package core
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kookehs/watchmen/primitives"
+)
+
+func mustIBAN(t *testing.T, b byte) primitives.IBAN {
+	t.Helper()
+	address := make([]byte, 20)
+	address[19] = b
+	iban, err := primitives.IBANFromAddress(primitives.MakeAddress(address))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return iban
+}
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		valid    bool
+	}{
+		{"bob", true},
+		{"alice_2", true},
+		{"007", true},
+		{strings.Repeat("a", MaxUsernameLength), true},
+		{"", false},
+		{"al", false},
+		{strings.Repeat("a", MaxUsernameLength+1), false},
+		{"Alice", false},
+		{"al-ice", false},
+		{"al ice", false},
+		{"alicé", false},
+		{"admin", false},
+		{"watchmen", false},
+	}
+
+	for _, test := range tests {
+		if err := ValidateUsername(test.username); (err == nil) != test.valid {
+			t.Errorf("ValidateUsername(%q) = %v, want valid %v", test.username, err, test.valid)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	alice, bob, carol := mustIBAN(t, 1), mustIBAN(t, 2), mustIBAN(t, 3)
+	none := primitives.IBAN{}
+
+	// Actions are applied in order to the same Registry.
+	tests := []struct {
+		action   primitives.NameAction
+		username string
+		dst      primitives.IBAN
+		src      primitives.IBAN
+		valid    bool
+	}{
+		{primitives.RegisterName, "alice", none, alice, true},
+		{primitives.RegisterName, "alice", none, bob, false},
+		{primitives.RegisterName, "alicia", none, alice, false},
+		{primitives.RegisterName, "Bob", none, bob, false},
+		{primitives.RegisterName, "root", none, bob, false},
+		{primitives.RegisterName, "bo", none, bob, false},
+		{primitives.ReleaseName, "alice", none, bob, false},
+		{primitives.ReleaseName, "carol", none, carol, false},
+		{primitives.TransferName, "alice", carol, bob, false},
+		{primitives.RegisterName, "bob", none, bob, true},
+		{primitives.TransferName, "alice", bob, alice, false},
+		{primitives.TransferName, "alice", carol, alice, true},
+		{primitives.ReleaseName, "alice", none, alice, false},
+		{primitives.TransferName, "alice", alice, alice, false},
+		{primitives.ReleaseName, "alice", none, carol, true},
+		{primitives.RegisterName, "alice", none, alice, true},
+		{primitives.NameAction(255), "carol", none, carol, false},
+	}
+
+	registry := NewRegistry()
+
+	for i, test := range tests {
+		checked := registry.Check(test.action, test.username, test.dst, test.src)
+
+		if (checked == nil) != test.valid {
+			t.Errorf("%v: Check(%v, %q) = %v, want valid %v", i, test.action, test.username, checked, test.valid)
+		}
+
+		applied := registry.Apply(test.action, test.username, test.dst, test.src)
+
+		if (applied == nil) != (checked == nil) {
+			t.Errorf("%v: Apply(%v, %q) = %v, Check = %v", i, test.action, test.username, applied, checked)
+		}
+
+		if len(registry.IBANs) != len(registry.Usernames) {
+			t.Fatalf("%v: Registry has %v usernames and %v IBANs", i, len(registry.IBANs), len(registry.Usernames))
+		}
+
+		for username, iban := range registry.IBANs {
+			if owned, _ := registry.Username(iban); owned != username {
+				t.Fatalf("%v: Username of %v = %q, want %q", i, iban.String(), owned, username)
+			}
+		}
+	}
+
+	want := map[primitives.IBAN]string{alice: "alice", bob: "bob", carol: ""}
+
+	for iban, username := range want {
+		if owned, _ := registry.Username(iban); owned != username {
+			t.Errorf("Username of %v = %q, want %q", iban.String(), owned, username)
+		}
+	}
+}
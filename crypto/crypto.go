@@ -1,9 +1,9 @@
 package crypto
 
 import (
+	"crypto/ecdh"
 	"crypto/ecdsa"
 	"crypto/elliptic"
-	"crypto/rand"
 	"crypto/sha256"
 	"encoding/gob"
 	"math/big"
@@ -29,12 +29,57 @@ func ECDSAPublicKeyToSHA256(pub ecdsa.PublicKey) [sha256.Size]byte {
 	return sha256.Sum256(octet[1:])
 }
 
+// p256ScalarBaseMult returns the point k*G on P-256 computed in constant time using crypto/ecdh.
+// k must be a 32 byte big-endian integer in the range [1, N).
+func p256ScalarBaseMult(k []byte) (*big.Int, *big.Int, error) {
+	priv, err := ecdh.P256().NewPrivateKey(k)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Public keys are encoded as 0x04 || X || Y.
+	point := priv.PublicKey().Bytes()
+	size := (len(point) - 1) / 2
+	x := new(big.Int).SetBytes(point[1 : 1+size])
+	y := new(big.Int).SetBytes(point[1+size:])
+	return x, y, nil
+}
+
+// CanonicalS returns the low form of the given S value of a P-256 signature.
+// Both S and N - S are valid so only the lower of the two is accepted.
+func CanonicalS(s *big.Int) *big.Int {
+	if IsLowS(s) {
+		return s
+	}
+
+	return new(big.Int).Sub(elliptic.P256().Params().N, s)
+}
+
+// IsLowS returns whether the given S value of a P-256 signature is at most half the curve order.
+func IsLowS(s *big.Int) bool {
+	half := new(big.Int).Rsh(elliptic.P256().Params().N, 1)
+	return s.Cmp(half) != 1
+}
+
 // Sign signs the given hash with the given private key.
+// Nonces are generated deterministically using RFC 6979 and S is always in low form.
 func Sign(hash []byte, priv *ecdsa.PrivateKey) (*big.Int, *big.Int, error) {
-	return ecdsa.Sign(rand.Reader, priv, hash)
+	r, s, err := SignDeterministic(hash, priv)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, CanonicalS(s), nil
 }
 
 // Verify verifies the given hash with the given public key.
+// Signatures with S in high form are rejected to prevent malleability.
 func Verify(hash []byte, pub *ecdsa.PublicKey, r, s *big.Int) bool {
+	if !IsLowS(s) {
+		return false
+	}
+
 	return ecdsa.Verify(pub, hash, r, s)
 }
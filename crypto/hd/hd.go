@@ -0,0 +1,213 @@
+package hd
+
+import (
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/kookehs/watchmen/crypto"
+)
+
+// HardenedOffset is added to an index to derive a hardened child.
+const HardenedOffset uint32 = 0x80000000
+
+// Defines the path scheme used for accounts
+var (
+	// Purpose and coin type of the path in the style of BIP44
+	Purpose  uint32 = 44
+	CoinType uint32 = 7777
+)
+
+// ExtendedKey is a private key and chain code from which child keys can be derived as described in SLIP-0010.
+// Only hardened derivation is supported so that P-256 and Ed25519 keys are derived the same way.
+type ExtendedKey struct {
+	ChainCode []byte
+	Depth     uint8
+	Key       []byte
+	Scheme    crypto.Scheme
+}
+
+// NewMasterKey returns the master ExtendedKey of the given scheme for the given seed.
+func NewMasterKey(scheme crypto.Scheme, seed []byte) (*ExtendedKey, error) {
+	var curve string
+
+	switch scheme {
+	case crypto.P256:
+		curve = "Nist256p1 seed"
+	case crypto.Ed25519:
+		curve = "ed25519 seed"
+	default:
+		return nil, errors.New("Unknown signature scheme")
+	}
+
+	i := hmacSHA512([]byte(curve), seed)
+
+	// Retry with the output until a valid P-256 key is found.
+	for (scheme == crypto.P256) && !validP256(i[:32]) {
+		i = hmacSHA512([]byte(curve), i)
+	}
+
+	return &ExtendedKey{
+		ChainCode: i[32:],
+		Depth:     0,
+		Key:       i[:32],
+		Scheme:    scheme,
+	}, nil
+}
+
+// Account returns the key for the given account and index following the path scheme.
+func (ek *ExtendedKey) Account(account, index uint32) (*ExtendedKey, error) {
+	return ek.DerivePath(AccountPath(account, index))
+}
+
+// Child returns the hardened child ExtendedKey at the given index.
+func (ek *ExtendedKey) Child(index uint32) (*ExtendedKey, error) {
+	if index < HardenedOffset {
+		return nil, fmt.Errorf("Only hardened derivation is supported: %v", index)
+	}
+
+	data := make([]byte, 0, 37)
+	data = append(data, 0x00)
+	data = append(data, ek.Key...)
+	data = append(data, ser32(index)...)
+	i := hmacSHA512(ek.ChainCode, data)
+	key := i[:32]
+
+	if ek.Scheme == crypto.P256 {
+		n := elliptic.P256().Params().N
+
+		for {
+			child := new(big.Int).SetBytes(i[:32])
+
+			if child.Cmp(n) == -1 {
+				child.Add(child, new(big.Int).SetBytes(ek.Key))
+				child.Mod(child, n)
+
+				if child.Sign() != 0 {
+					key = child.FillBytes(make([]byte, 32))
+					break
+				}
+			}
+
+			data = append([]byte{0x01}, i[32:]...)
+			data = append(data, ser32(index)...)
+			i = hmacSHA512(ek.ChainCode, data)
+		}
+	}
+
+	return &ExtendedKey{
+		ChainCode: i[32:],
+		Depth:     ek.Depth + 1,
+		Key:       key,
+		Scheme:    ek.Scheme,
+	}, nil
+}
+
+// DerivePath returns the ExtendedKey at the given path relative to this key.
+func (ek *ExtendedKey) DerivePath(path []uint32) (*ExtendedKey, error) {
+	key := ek
+
+	for _, index := range path {
+		child, err := key.Child(index)
+
+		if err != nil {
+			return nil, err
+		}
+
+		key = child
+	}
+
+	return key, nil
+}
+
+// Signer returns the private key of the ExtendedKey.
+func (ek *ExtendedKey) Signer() (crypto.Signer, error) {
+	return crypto.ParseSigner(ek.Scheme, ek.Key)
+}
+
+// AccountPath returns the path m/Purpose'/CoinType'/account'/0'/index' with every level hardened.
+func AccountPath(account, index uint32) []uint32 {
+	return []uint32{
+		Purpose + HardenedOffset,
+		CoinType + HardenedOffset,
+		account + HardenedOffset,
+		HardenedOffset,
+		index + HardenedOffset,
+	}
+}
+
+// ParsePath parses a path such as m/44'/7777'/0'/0'/0' into indices.
+// Both ' and h mark an index as hardened.
+func ParsePath(path string) ([]uint32, error) {
+	segments := strings.Split(path, "/")
+
+	if (len(segments) == 0) || (segments[0] != "m") {
+		return nil, fmt.Errorf("Path must start with m: %v", path)
+	}
+
+	indices := make([]uint32, 0, len(segments)-1)
+
+	for _, segment := range segments[1:] {
+		offset := uint32(0)
+
+		if strings.HasSuffix(segment, "'") || strings.HasSuffix(segment, "h") {
+			offset = HardenedOffset
+			segment = segment[:len(segment)-1]
+		}
+
+		index, err := strconv.ParseUint(segment, 10, 31)
+
+		if err != nil {
+			return nil, fmt.Errorf("Invalid path segment %q", segment)
+		}
+
+		indices = append(indices, uint32(index)+offset)
+	}
+
+	return indices, nil
+}
+
+// FormatPath returns the string representation of the given indices.
+func FormatPath(path []uint32) string {
+	var builder strings.Builder
+	builder.WriteString("m")
+
+	for _, index := range path {
+		builder.WriteString("/")
+
+		if index >= HardenedOffset {
+			builder.WriteString(strconv.FormatUint(uint64(index-HardenedOffset), 10))
+			builder.WriteString("'")
+		} else {
+			builder.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+
+	return builder.String()
+}
+
+// hmacSHA512 returns the HMAC-SHA512 of the given data using the given key.
+func hmacSHA512(key, data []byte) []byte {
+	mac := hmac.New(sha512.New, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// ser32 returns the given index as 4 big-endian bytes.
+func ser32(index uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, index)
+	return b
+}
+
+// validP256 returns whether the given bytes are a valid P-256 private key.
+func validP256(key []byte) bool {
+	integer := new(big.Int).SetBytes(key)
+	return (integer.Sign() != 0) && (integer.Cmp(elliptic.P256().Params().N) == -1)
+}
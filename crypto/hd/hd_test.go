@@ -0,0 +1,95 @@
+package hd
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/kookehs/watchmen/crypto"
+)
+
+func TestSLIP10Vectors(t *testing.T) {
+	// Test vectors from SLIP-0010 restricted to hardened derivation
+	tests := []struct {
+		scheme    crypto.Scheme
+		seed      string
+		path      string
+		chainCode string
+		key       string
+	}{
+		{crypto.P256, "000102030405060708090a0b0c0d0e0f", "m",
+			"beeb672fe4621673f722f38529c07392fecaa61015c80c34f29ce8b41b3cb6ea",
+			"612091aaa12e22dd2abef664f8a01a82cae99ad7441b7ef8110424915c268bc2"},
+		{crypto.P256, "000102030405060708090a0b0c0d0e0f", "m/0'",
+			"3460cea53e6a6bb5fb391eeef3237ffd8724bf0a40e94943c98b83825342ee11",
+			"6939694369114c67917a182c59ddb8cafc3004e63ca5d3b84403ba8613debc0c"},
+		// Derivation retry for nist256p1
+		{crypto.P256, "000102030405060708090a0b0c0d0e0f", "m/28578'",
+			"e94c8ebe30c2250a14713212f6449b20f3329105ea15b652ca5bdfc68f6c65c2",
+			"06f0db126f023755d0b8d86d4591718a5210dd8d024e3e14b6159d63f53aa669"},
+		// Seed retry for nist256p1
+		{crypto.P256, "a7305bc8df8d0951f0cb224c0e95d7707cbdf2c6ce7e8d481fec69c7ff5e9446", "m",
+			"7762f9729fed06121fd13f326884c82f59aa95c57ac492ce8c9654e60efd130c",
+			"3b8c18469a4634517d6d0b65448f8e6c62091b45540a1743c5846be55d47d88f"},
+		{crypto.Ed25519, "000102030405060708090a0b0c0d0e0f", "m",
+			"90046a93de5380a72b5e45010748567d5ea02bbf6522f979e05c0d8d8ca9fffb",
+			"2b4be7f19ee27bbf30c667b642d5f4aa69fd169872f8fc3059c08ebae2eb19e7"},
+		{crypto.Ed25519, "000102030405060708090a0b0c0d0e0f", "m/0'",
+			"8b59aa11380b624e81507a27fedda59fea6d0b779a778918a2fd3590e16e9c69",
+			"68e0fe46dfb67e368c75379acec591dad19df3cde26e63b93a8e704f1dade7a3"},
+		{crypto.Ed25519, "000102030405060708090a0b0c0d0e0f", "m/0'/1'",
+			"a320425f77d1b5c2505a6b1b27382b37368ee640e3557c315416801243552f14",
+			"b1d0bad404bf35da785a64ca1ac54b2617211d2777696fbffaf208f746ae84f2"},
+		{crypto.Ed25519, "000102030405060708090a0b0c0d0e0f", "m/0'/1'/2'",
+			"2e69929e00b5ab250f49c3fb1c12f252de4fed2c1db88387094a0f8c4c9ccd6c",
+			"92a5b23c0b8a99e37d07df3fb9966917f5d06e02ddbd909c7e184371463e9fc9"},
+	}
+
+	for _, test := range tests {
+		seed, _ := hex.DecodeString(test.seed)
+		master, err := NewMasterKey(test.scheme, seed)
+
+		if err != nil {
+			t.Fatalf("NewMasterKey(%v): %v", test.seed, err)
+		}
+
+		path, err := ParsePath(test.path)
+
+		if err != nil {
+			t.Fatalf("ParsePath(%q): %v", test.path, err)
+		}
+
+		key, err := master.DerivePath(path)
+
+		if err != nil {
+			t.Fatalf("DerivePath(%q): %v", test.path, err)
+		}
+
+		if got := hex.EncodeToString(key.ChainCode); got != test.chainCode {
+			t.Errorf("Chain code of %v %v = %v, want %v", test.seed, test.path, got, test.chainCode)
+		}
+
+		if got := hex.EncodeToString(key.Key); got != test.key {
+			t.Errorf("Key of %v %v = %v, want %v", test.seed, test.path, got, test.key)
+		}
+
+		if int(key.Depth) != len(path) {
+			t.Errorf("Depth of %v = %v, want %v", test.path, key.Depth, len(path))
+		}
+
+		if _, err := key.Signer(); err != nil {
+			t.Errorf("Signer of %v %v: %v", test.seed, test.path, err)
+		}
+	}
+}
+
+func TestChildRejectsNormalDerivation(t *testing.T) {
+	master, err := NewMasterKey(crypto.Ed25519, make([]byte, 16))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := master.Child(1); err == nil {
+		t.Error("Child(1) succeeded without hardening")
+	}
+}
@@ -0,0 +1,137 @@
+package hd
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// Limits of the entropy used to generate mnemonics in bits
+const (
+	MaxEntropyBits = 256
+	MinEntropyBits = 128
+)
+
+// Reasons a mnemonic can be invalid
+var (
+	ErrMnemonicChecksum = errors.New("Invalid mnemonic checksum")
+	ErrMnemonicLength   = errors.New("Invalid number of words in mnemonic")
+)
+
+// GenerateMnemonic returns a new mnemonic encoding the given number of bits read from the given source of randomness.
+func GenerateMnemonic(bits int, r io.Reader) (string, error) {
+	entropy := make([]byte, bits/8)
+
+	if err := validateEntropyBits(bits); err != nil {
+		return "", err
+	}
+
+	if _, err := io.ReadFull(r, entropy); err != nil {
+		return "", err
+	}
+
+	return NewMnemonic(entropy)
+}
+
+// NewMnemonic returns the mnemonic encoding the given entropy followed by its checksum.
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+
+	if err := validateEntropyBits(bits); err != nil {
+		return "", err
+	}
+
+	checksumBits := uint(bits / 32)
+	hash := sha256.Sum256(entropy)
+
+	// Append the leftmost bits of the hash to the entropy.
+	integer := new(big.Int).SetBytes(entropy)
+	integer.Lsh(integer, checksumBits)
+	integer.Or(integer, big.NewInt(int64(hash[0]>>(8-checksumBits))))
+
+	count := (bits + int(checksumBits)) / 11
+	words := make([]string, count)
+	mask := big.NewInt(2047)
+
+	for i := count - 1; i >= 0; i-- {
+		index := new(big.Int).And(integer, mask)
+		words[i] = English[index.Int64()]
+		integer.Rsh(integer, 11)
+	}
+
+	return strings.Join(words, " "), nil
+}
+
+// MnemonicToEntropy validates the given mnemonic and returns the entropy it encodes.
+func MnemonicToEntropy(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	count := len(words)
+
+	if (count%3 != 0) || (count*11*32/33 < MinEntropyBits) || (count*11*32/33 > MaxEntropyBits) {
+		return nil, ErrMnemonicLength
+	}
+
+	integer := new(big.Int)
+
+	for _, word := range words {
+		index, exist := wordIndex[word]
+
+		if !exist {
+			return nil, fmt.Errorf("Unknown mnemonic word %q", word)
+		}
+
+		integer.Lsh(integer, 11)
+		integer.Or(integer, big.NewInt(int64(index)))
+	}
+
+	bits := count * 11 * 32 / 33
+	checksumBits := uint(count * 11 / 33)
+	checksum := new(big.Int).And(integer, big.NewInt(int64(1)<<checksumBits-1))
+	integer.Rsh(integer, checksumBits)
+
+	entropy := make([]byte, bits/8)
+	integer.FillBytes(entropy)
+	hash := sha256.Sum256(entropy)
+
+	if checksum.Int64() != int64(hash[0]>>(8-checksumBits)) {
+		return nil, ErrMnemonicChecksum
+	}
+
+	return entropy, nil
+}
+
+// MnemonicToSeed validates the given mnemonic and returns the 64 byte seed protected by the given passphrase.
+// Mnemonics and passphrases are expected to be ASCII as no Unicode normalization is performed.
+func MnemonicToSeed(mnemonic, passphrase string) ([]byte, error) {
+	if _, err := MnemonicToEntropy(mnemonic); err != nil {
+		return nil, err
+	}
+
+	normalized := strings.Join(strings.Fields(mnemonic), " ")
+	return pbkdf2.Key(sha512.New, normalized, []byte("mnemonic"+passphrase), 2048, 64)
+}
+
+// validateEntropyBits returns whether the given number of bits can be encoded as a mnemonic.
+func validateEntropyBits(bits int) error {
+	if (bits%32 != 0) || (bits < MinEntropyBits) || (bits > MaxEntropyBits) {
+		return fmt.Errorf("Entropy must be a multiple of 32 bits between %v and %v", MinEntropyBits, MaxEntropyBits)
+	}
+
+	return nil
+}
+
+// wordIndex maps each word in English to its position.
+var wordIndex = func() map[string]int {
+	index := make(map[string]int, len(English))
+
+	for i, word := range English {
+		index[word] = i
+	}
+
+	return index
+}()
@@ -0,0 +1,77 @@
+package hd
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestMnemonicVectors(t *testing.T) {
+	// Test vectors from BIP39 using the passphrase TREZOR
+	tests := []struct {
+		entropy  string
+		mnemonic string
+		seed     string
+	}{
+		{
+			entropy:  "00000000000000000000000000000000",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+		},
+		{
+			entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+			mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+		},
+		{
+			entropy:  "ffffffffffffffffffffffffffffffff",
+			mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			seed:     "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+		},
+	}
+
+	for _, test := range tests {
+		entropy, _ := hex.DecodeString(test.entropy)
+		mnemonic, err := NewMnemonic(entropy)
+
+		if err != nil {
+			t.Fatalf("NewMnemonic(%v): %v", test.entropy, err)
+		}
+
+		if mnemonic != test.mnemonic {
+			t.Errorf("NewMnemonic(%v) = %q, want %q", test.entropy, mnemonic, test.mnemonic)
+		}
+
+		decoded, err := MnemonicToEntropy(test.mnemonic)
+
+		if (err != nil) || (hex.EncodeToString(decoded) != test.entropy) {
+			t.Errorf("MnemonicToEntropy(%q) = %x, %v", test.mnemonic, decoded, err)
+		}
+
+		seed, err := MnemonicToSeed(test.mnemonic, "TREZOR")
+
+		if err != nil {
+			t.Fatalf("MnemonicToSeed(%q): %v", test.mnemonic, err)
+		}
+
+		if hex.EncodeToString(seed) != test.seed {
+			t.Errorf("MnemonicToSeed(%q) = %x, want %v", test.mnemonic, seed, test.seed)
+		}
+	}
+}
+
+func TestMnemonicErrors(t *testing.T) {
+	valid := "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+	if _, err := MnemonicToSeed(strings.Replace(valid, "about", "abandon", 1), ""); err != ErrMnemonicChecksum {
+		t.Errorf("MnemonicToSeed with bad checksum = %v, want %v", err, ErrMnemonicChecksum)
+	}
+
+	if _, err := MnemonicToSeed(strings.TrimSuffix(valid, " about"), ""); err != ErrMnemonicLength {
+		t.Errorf("MnemonicToSeed with 11 words = %v, want %v", err, ErrMnemonicLength)
+	}
+
+	if _, err := MnemonicToSeed(strings.Replace(valid, "about", "notaword", 1), ""); err == nil {
+		t.Error("MnemonicToSeed with unknown word succeeded")
+	}
+}
@@ -0,0 +1,198 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"hash"
+	"math/big"
+)
+
+// SignDeterministic signs the given hash with a nonce derived from the private key and hash as described in RFC 6979.
+// Signing the same hash with the same key always results in the same signature.
+// Only P-256 keys are supported since nonces are multiplied by the base point in constant time using crypto/ecdh.
+func SignDeterministic(hash []byte, priv *ecdsa.PrivateKey) (*big.Int, *big.Int, error) {
+	if (priv == nil) || (priv.D == nil) || (priv.Curve == nil) || (priv.Curve.Params().Name != "P-256") {
+		return nil, nil, errors.New("Invalid P-256 private key")
+	}
+
+	n := priv.Curve.Params().N
+	length := (n.BitLen() + 7) / 8
+	e := hashToInt(hash, n)
+	nonce := newRFC6979(sha256.New, priv.D, hash, n)
+
+	for {
+		k := nonce.next()
+		x, _, err := p256ScalarBaseMult(int2octets(k, length))
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r := new(big.Int).Mod(x, n)
+
+		if r.Sign() == 0 {
+			continue
+		}
+
+		kInv, err := invert(k, n)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		s := new(big.Int).Mul(priv.D, r)
+		s.Add(s, e)
+		s.Mul(s, kInv)
+		s.Mod(s, n)
+
+		if s.Sign() == 0 {
+			continue
+		}
+
+		return r, s, nil
+	}
+}
+
+// invert returns the inverse of k modulo n.
+// k is multiplied by a random blinding factor first so that the time taken does not reveal k.
+func invert(k, n *big.Int) (*big.Int, error) {
+	one := big.NewInt(1)
+	blind, err := rand.Int(rand.Reader, new(big.Int).Sub(n, one))
+
+	if err != nil {
+		return nil, err
+	}
+
+	blind.Add(blind, one)
+	blinded := new(big.Int).Mul(k, blind)
+	blinded.Mod(blinded, n)
+	inverse := new(big.Int).ModInverse(blinded, n)
+
+	if inverse == nil {
+		return nil, errors.New("Nonce is not invertible")
+	}
+
+	inverse.Mul(inverse, blind)
+	return inverse.Mod(inverse, n), nil
+}
+
+// rfc6979 generates the sequence of candidate nonces from section 3.2 of RFC 6979.
+type rfc6979 struct {
+	h     func() hash.Hash
+	k     []byte
+	n     *big.Int
+	first bool
+	v     []byte
+}
+
+// newRFC6979 initializes the HMAC_DRBG state with the given private key and hash.
+func newRFC6979(h func() hash.Hash, d *big.Int, digest []byte, n *big.Int) *rfc6979 {
+	size := h().Size()
+	length := (n.BitLen() + 7) / 8
+	key := int2octets(d, length)
+	message := bits2octets(digest, n, length)
+
+	nonce := &rfc6979{
+		h:     h,
+		k:     make([]byte, size),
+		n:     n,
+		first: true,
+		v:     make([]byte, size),
+	}
+
+	for i := range nonce.v {
+		nonce.v[i] = 0x01
+	}
+
+	nonce.k = nonce.mac(nonce.k, nonce.v, []byte{0x00}, key, message)
+	nonce.v = nonce.mac(nonce.k, nonce.v)
+	nonce.k = nonce.mac(nonce.k, nonce.v, []byte{0x01}, key, message)
+	nonce.v = nonce.mac(nonce.k, nonce.v)
+	return nonce
+}
+
+// next returns the next candidate nonce in the range [1, n).
+func (r *rfc6979) next() *big.Int {
+	length := (r.n.BitLen() + 7) / 8
+
+	for {
+		if !r.first {
+			r.k = r.mac(r.k, r.v, []byte{0x00})
+			r.v = r.mac(r.k, r.v)
+		}
+
+		r.first = false
+		t := make([]byte, 0, length)
+
+		for len(t) < length {
+			r.v = r.mac(r.k, r.v)
+			t = append(t, r.v...)
+		}
+
+		k := bits2int(t, r.n)
+
+		if (k.Sign() > 0) && (k.Cmp(r.n) == -1) {
+			return k
+		}
+	}
+}
+
+// mac returns the HMAC of the concatenation of the given data using the given key.
+func (r *rfc6979) mac(key []byte, data ...[]byte) []byte {
+	m := hmac.New(r.h, key)
+
+	for _, d := range data {
+		m.Write(d)
+	}
+
+	return m.Sum(nil)
+}
+
+// bits2int converts the leftmost bits of the given bytes to an integer no longer than n.
+func bits2int(b []byte, n *big.Int) *big.Int {
+	integer := new(big.Int).SetBytes(b)
+
+	if excess := len(b)*8 - n.BitLen(); excess > 0 {
+		integer.Rsh(integer, uint(excess))
+	}
+
+	return integer
+}
+
+// bits2octets converts the given hash to an integer modulo n encoded in length bytes.
+func bits2octets(b []byte, n *big.Int, length int) []byte {
+	integer := bits2int(b, n)
+
+	if integer.Cmp(n) != -1 {
+		integer.Sub(integer, n)
+	}
+
+	return int2octets(integer, length)
+}
+
+// int2octets encodes the given integer in length bytes.
+func int2octets(integer *big.Int, length int) []byte {
+	b := integer.Bytes()
+
+	if len(b) > length {
+		return b[len(b)-length:]
+	}
+
+	octets := make([]byte, length)
+	copy(octets[length-len(b):], b)
+	return octets
+}
+
+// hashToInt converts the given hash to an integer as done by ECDSA.
+func hashToInt(hash []byte, n *big.Int) *big.Int {
+	length := (n.BitLen() + 7) / 8
+
+	if len(hash) > length {
+		hash = hash[:length]
+	}
+
+	return bits2int(hash, n)
+}
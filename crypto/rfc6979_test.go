@@ -0,0 +1,111 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"math/big"
+	"testing"
+)
+
+func hexInt(t *testing.T, s string) *big.Int {
+	t.Helper()
+	integer, ok := new(big.Int).SetString(s, 16)
+
+	if !ok {
+		t.Fatalf("Invalid hex integer %q", s)
+	}
+
+	return integer
+}
+
+// rfc6979Key returns the P-256 key of RFC 6979 A.2.5.
+func rfc6979Key(t *testing.T) *ecdsa.PrivateKey {
+	t.Helper()
+	signer, err := ParseSigner(P256, hexInt(t, "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721").Bytes())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer.(*P256Signer).PrivateKey
+}
+
+func TestRFC6979PublicKey(t *testing.T) {
+	priv := rfc6979Key(t)
+	x := hexInt(t, "60FED4BA255A9D31C961EB74C6356D68C049B8923B61FA6CE669622E60F29FB6")
+	y := hexInt(t, "7903FE1008B8BC99A41AE9E95628BC64F2F1B20C2D7E9F5177A3C294D4462299")
+
+	if (priv.X.Cmp(x) != 0) || (priv.Y.Cmp(y) != 0) {
+		t.Errorf("Public key = (%X, %X), want (%X, %X)", priv.X, priv.Y, x, y)
+	}
+}
+
+func TestSignDeterministic(t *testing.T) {
+	// RFC 6979 A.2.5 with SHA-256
+	tests := []struct {
+		message string
+		k       string
+		r       string
+		s       string
+	}{
+		{
+			message: "sample",
+			k:       "A6E3C57DD01ABE90086538398355DD4C3B17AA873382B0F24D6129493D8AAD60",
+			r:       "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716",
+			s:       "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8",
+		},
+		{
+			message: "test",
+			k:       "D16B6AE827F17175E040871A1C7EC3500192C4C92677336EC2537ACAEE0008E0",
+			r:       "F1ABB023518351CD71D881567B1EA663ED3EFCF6C5132B354F28D3B0B7D38367",
+			s:       "019F4113742A2B14BD25926B49C649155F267E60D3814B4C0CC84250E46F0083",
+		},
+	}
+
+	priv := rfc6979Key(t)
+	n := priv.Curve.Params().N
+
+	for _, test := range tests {
+		hash := sha256.Sum256([]byte(test.message))
+
+		if k := newRFC6979(sha256.New, priv.D, hash[:], n).next(); k.Cmp(hexInt(t, test.k)) != 0 {
+			t.Errorf("Nonce for %q = %X, want %v", test.message, k, test.k)
+		}
+
+		r, s, err := SignDeterministic(hash[:], priv)
+
+		if err != nil {
+			t.Fatalf("SignDeterministic(%q): %v", test.message, err)
+		}
+
+		if (r.Cmp(hexInt(t, test.r)) != 0) || (s.Cmp(hexInt(t, test.s)) != 0) {
+			t.Errorf("SignDeterministic(%q) = (%X, %X), want (%v, %v)", test.message, r, s, test.r, test.s)
+		}
+
+		r, s, err = Sign(hash[:], priv)
+
+		if err != nil {
+			t.Fatalf("Sign(%q): %v", test.message, err)
+		}
+
+		if !IsLowS(s) || !Verify(hash[:], &priv.PublicKey, r, s) {
+			t.Errorf("Sign(%q) = (%X, %X) does not verify in low form", test.message, r, s)
+		}
+	}
+}
+
+func TestSignDeterministicRejectsOtherCurves(t *testing.T) {
+	priv, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := sha256.Sum256([]byte("sample"))
+
+	if _, _, err := SignDeterministic(hash[:], priv); err == nil {
+		t.Error("SignDeterministic with a P-384 key succeeded")
+	}
+}
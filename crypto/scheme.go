@@ -0,0 +1,272 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// Scheme identifies the algorithm used to create and verify signatures.
+type Scheme uint8
+
+// Various signature schemes
+const (
+	P256 Scheme = iota
+	Ed25519
+)
+
+// String returns the name of the Scheme.
+func (s Scheme) String() string {
+	switch s {
+	case P256:
+		return "P-256"
+	case Ed25519:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("Scheme(%d)", uint8(s))
+	}
+}
+
+// Signer is implemented by private keys capable of signing hashes.
+// Signatures are returned as a pair of integers regardless of the scheme.
+type Signer interface {
+	Scheme() Scheme
+	Sign(hash []byte) (*big.Int, *big.Int, error)
+	Verifier() Verifier
+}
+
+// Verifier is implemented by public keys capable of verifying signatures.
+type Verifier interface {
+	Bytes() []byte
+	Scheme() Scheme
+	Verify(hash []byte, r, s *big.Int) bool
+}
+
+// GenerateSigner generates a private key for the given scheme using the given source of randomness.
+func GenerateSigner(scheme Scheme, r io.Reader) (Signer, error) {
+	switch scheme {
+	case P256:
+		priv, err := ecdsa.GenerateKey(elliptic.P256(), r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return NewP256Signer(priv), nil
+	case Ed25519:
+		_, priv, err := ed25519.GenerateKey(r)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return NewEd25519Signer(priv), nil
+	default:
+		return nil, errors.New("Unknown signature scheme")
+	}
+}
+
+// ParseVerifier returns the public key of the given scheme encoded in the given bytes as returned by Verifier.Bytes.
+func ParseVerifier(scheme Scheme, b []byte) (Verifier, error) {
+	switch scheme {
+	case P256:
+		x, y := elliptic.Unmarshal(elliptic.P256(), b)
+
+		if x == nil {
+			return nil, errors.New("Invalid P-256 public key")
+		}
+
+		return NewP256Verifier(&ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}), nil
+	case Ed25519:
+		if len(b) != ed25519.PublicKeySize {
+			return nil, errors.New("Invalid Ed25519 public key")
+		}
+
+		pub := make(ed25519.PublicKey, ed25519.PublicKeySize)
+		copy(pub, b)
+		return NewEd25519Verifier(pub), nil
+	default:
+		return nil, errors.New("Unknown signature scheme")
+	}
+}
+
+// ParseSigner returns the private key of the given scheme encoded in the given bytes.
+// P-256 keys are encoded as the big-endian scalar and Ed25519 keys as the seed.
+func ParseSigner(scheme Scheme, b []byte) (Signer, error) {
+	switch scheme {
+	case P256:
+		curve := elliptic.P256()
+		d := new(big.Int).SetBytes(b)
+
+		if (d.Sign() == 0) || (d.Cmp(curve.Params().N) >= 0) {
+			return nil, errors.New("Invalid P-256 private key")
+		}
+
+		x, y, err := p256ScalarBaseMult(d.FillBytes(make([]byte, 32)))
+
+		if err != nil {
+			return nil, err
+		}
+
+		priv := &ecdsa.PrivateKey{D: d}
+		priv.PublicKey.Curve = curve
+		priv.PublicKey.X, priv.PublicKey.Y = x, y
+		return NewP256Signer(priv), nil
+	case Ed25519:
+		if len(b) != ed25519.SeedSize {
+			return nil, errors.New("Invalid Ed25519 private key")
+		}
+
+		return NewEd25519Signer(ed25519.NewKeyFromSeed(b)), nil
+	default:
+		return nil, errors.New("Unknown signature scheme")
+	}
+}
+
+// PublicKeyToSHA256 returns the SHA256 hash of the given public key.
+// P-256 keys are hashed without the leading octet to match ECDSAPublicKeyToSHA256.
+func PublicKeyToSHA256(pub Verifier) [sha256.Size]byte {
+	if verifier, ok := pub.(*P256Verifier); ok {
+		return ECDSAPublicKeyToSHA256(*verifier.PublicKey)
+	}
+
+	return sha256.Sum256(pub.Bytes())
+}
+
+// P256Signer signs hashes using ECDSA on the P-256 curve.
+type P256Signer struct {
+	PrivateKey *ecdsa.PrivateKey
+}
+
+// NewP256Signer returns a pointer to a P256Signer for the given private key.
+func NewP256Signer(priv *ecdsa.PrivateKey) *P256Signer {
+	return &P256Signer{
+		PrivateKey: priv,
+	}
+}
+
+// Scheme returns P256.
+func (ps *P256Signer) Scheme() Scheme {
+	return P256
+}
+
+// Sign signs the given hash.
+func (ps *P256Signer) Sign(hash []byte) (*big.Int, *big.Int, error) {
+	return Sign(hash, ps.PrivateKey)
+}
+
+// Verifier returns the public key of the signer.
+func (ps *P256Signer) Verifier() Verifier {
+	return NewP256Verifier(&ps.PrivateKey.PublicKey)
+}
+
+// P256Verifier verifies ECDSA signatures on the P-256 curve.
+type P256Verifier struct {
+	PublicKey *ecdsa.PublicKey
+}
+
+// NewP256Verifier returns a pointer to a P256Verifier for the given public key.
+func NewP256Verifier(pub *ecdsa.PublicKey) *P256Verifier {
+	return &P256Verifier{
+		PublicKey: pub,
+	}
+}
+
+// Bytes returns the public key in uncompressed octet representation.
+func (pv *P256Verifier) Bytes() []byte {
+	return ECDSAPublicKeyToOctet(pv.PublicKey)
+}
+
+// Scheme returns P256.
+func (pv *P256Verifier) Scheme() Scheme {
+	return P256
+}
+
+// Verify verifies the given hash against the signature.
+func (pv *P256Verifier) Verify(hash []byte, r, s *big.Int) bool {
+	if (pv.PublicKey == nil) || (r == nil) || (s == nil) {
+		return false
+	}
+
+	return Verify(hash, pv.PublicKey, r, s)
+}
+
+// Ed25519Signer signs hashes using Ed25519.
+type Ed25519Signer struct {
+	PrivateKey ed25519.PrivateKey
+}
+
+// NewEd25519Signer returns a pointer to an Ed25519Signer for the given private key.
+func NewEd25519Signer(priv ed25519.PrivateKey) *Ed25519Signer {
+	return &Ed25519Signer{
+		PrivateKey: priv,
+	}
+}
+
+// Scheme returns Ed25519.
+func (es *Ed25519Signer) Scheme() Scheme {
+	return Ed25519
+}
+
+// Sign signs the given hash returning the two halves of the signature.
+func (es *Ed25519Signer) Sign(hash []byte) (*big.Int, *big.Int, error) {
+	if len(es.PrivateKey) != ed25519.PrivateKeySize {
+		return nil, nil, errors.New("Invalid Ed25519 private key")
+	}
+
+	signature := ed25519.Sign(es.PrivateKey, hash)
+	half := ed25519.SignatureSize / 2
+	r := new(big.Int).SetBytes(signature[:half])
+	s := new(big.Int).SetBytes(signature[half:])
+	return r, s, nil
+}
+
+// Verifier returns the public key of the signer.
+func (es *Ed25519Signer) Verifier() Verifier {
+	return NewEd25519Verifier(es.PrivateKey.Public().(ed25519.PublicKey))
+}
+
+// Ed25519Verifier verifies Ed25519 signatures.
+type Ed25519Verifier struct {
+	PublicKey ed25519.PublicKey
+}
+
+// NewEd25519Verifier returns a pointer to an Ed25519Verifier for the given public key.
+func NewEd25519Verifier(pub ed25519.PublicKey) *Ed25519Verifier {
+	return &Ed25519Verifier{
+		PublicKey: pub,
+	}
+}
+
+// Bytes returns the public key.
+func (ev *Ed25519Verifier) Bytes() []byte {
+	return []byte(ev.PublicKey)
+}
+
+// Scheme returns Ed25519.
+func (ev *Ed25519Verifier) Scheme() Scheme {
+	return Ed25519
+}
+
+// Verify verifies the given hash against the two halves of the signature.
+func (ev *Ed25519Verifier) Verify(hash []byte, r, s *big.Int) bool {
+	if (len(ev.PublicKey) != ed25519.PublicKeySize) || (r == nil) || (s == nil) {
+		return false
+	}
+
+	half := ed25519.SignatureSize / 2
+
+	if (r.BitLen() > half*8) || (s.BitLen() > half*8) || (r.Sign() < 0) || (s.Sign() < 0) {
+		return false
+	}
+
+	signature := make([]byte, ed25519.SignatureSize)
+	r.FillBytes(signature[:half])
+	s.FillBytes(signature[half:])
+	return ed25519.Verify(ev.PublicKey, hash, signature)
+}
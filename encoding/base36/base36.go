@@ -1,17 +1,119 @@
 package base36
 
 import (
+	"fmt"
 	"math/big"
 	"strings"
 )
 
-// Encode returns the base36 encoding of the given bytes.
-func Encode(b []byte) []byte {
-	if b[0] == '0' && b[1] == 'x' {
-		b = b[2:]
+// Alphabet contains the digits used for encoding in order of their value.
+const Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// CorruptInputError is the offset of an invalid character in the input.
+type CorruptInputError int64
+
+// Error returns the offset of the invalid character.
+func (e CorruptInputError) Error() string {
+	return fmt.Sprintf("Invalid base36 character at input byte %v", int64(e))
+}
+
+// SizeError is returned when a value does not fit in the requested width.
+type SizeError struct {
+	Length int
+	Width  int
+}
+
+// Error returns the length of the value and the width it exceeded.
+func (e *SizeError) Error() string {
+	return fmt.Sprintf("Length of %v exceeds fixed width of %v", e.Length, e.Width)
+}
+
+// Encode returns the base36 encoding of the given bytes interpreted as a big-endian unsigned integer.
+// Leading zero bytes are not preserved and an empty input results in an empty output.
+func Encode(src []byte) []byte {
+	if len(src) == 0 {
+		return []byte{}
 	}
 
-	integer := new(big.Int)
-	integer.SetString(string(b), 16)
+	integer := new(big.Int).SetBytes(src)
 	return []byte(strings.ToUpper(integer.Text(36)))
 }
+
+// EncodeFixed returns the base36 encoding of the given bytes left padded with zeros to width characters.
+func EncodeFixed(src []byte, width int) ([]byte, error) {
+	encoded := Encode(src)
+
+	if len(encoded) == 0 {
+		encoded = []byte{'0'}
+	}
+
+	if len(encoded) > width {
+		return nil, &SizeError{Length: len(encoded), Width: width}
+	}
+
+	dst := make([]byte, width-len(encoded), width)
+
+	for i := range dst {
+		dst[i] = '0'
+	}
+
+	return append(dst, encoded...), nil
+}
+
+// EncodeToString returns the base36 encoding of the given bytes as a string.
+func EncodeToString(src []byte) string {
+	return string(Encode(src))
+}
+
+// Decode returns the big-endian bytes of the given base36 encoding without leading zero bytes.
+// Both uppercase and lowercase letters are accepted.
+func Decode(src []byte) ([]byte, error) {
+	if len(src) == 0 {
+		return []byte{}, nil
+	}
+
+	for i, c := range src {
+		if strings.IndexByte(Alphabet, upper(c)) == -1 {
+			return nil, CorruptInputError(i)
+		}
+	}
+
+	integer, ok := new(big.Int).SetString(string(src), 36)
+
+	if !ok {
+		return nil, CorruptInputError(0)
+	}
+
+	return integer.Bytes(), nil
+}
+
+// DecodeFixed returns the big-endian bytes of the given base36 encoding left padded with zeros to size bytes.
+func DecodeFixed(src []byte, size int) ([]byte, error) {
+	decoded, err := Decode(src)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(decoded) > size {
+		return nil, &SizeError{Length: len(decoded), Width: size}
+	}
+
+	dst := make([]byte, size)
+	copy(dst[size-len(decoded):], decoded)
+	return dst, nil
+}
+
+// DecodeString returns the big-endian bytes of the given base36 string.
+func DecodeString(s string) ([]byte, error) {
+	return Decode([]byte(s))
+}
+
+// upper returns the uppercase form of the given ASCII letter.
+func upper(c byte) byte {
+	if (c >= 'a') && (c <= 'z') {
+		return c - 'a' + 'A'
+	}
+
+	return c
+}
@@ -0,0 +1,137 @@
+package base36
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+// width returns the number of characters needed to encode any value of size bytes.
+// Zero is encoded as a single character.
+func width(size int) int {
+	return max(1, int(math.Ceil(float64(size*8)/math.Log2(36))))
+}
+
+func FuzzEncodeDecode(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0})
+	f.Add([]byte{0, 0, 1})
+	f.Add([]byte{35})
+	f.Add([]byte{36})
+	f.Add(bytes.Repeat([]byte{0xff}, 20))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		encoded := Encode(b)
+
+		for i, c := range encoded {
+			if bytes.IndexByte([]byte(Alphabet), c) == -1 {
+				t.Fatalf("Encode(%x) contains %q at %v", b, c, i)
+			}
+		}
+
+		decoded, err := Decode(encoded)
+
+		if err != nil {
+			t.Fatalf("Decode(%q): %v", encoded, err)
+		}
+
+		// Leading zero bytes are not preserved by Encode.
+		if trimmed := bytes.TrimLeft(b, "\x00"); !bytes.Equal(decoded, trimmed) {
+			t.Fatalf("Decode(Encode(%x)) = %x", b, decoded)
+		}
+
+		lower, err := Decode(bytes.ToLower(encoded))
+
+		if (err != nil) || !bytes.Equal(lower, decoded) {
+			t.Fatalf("Decode(%q) = %x, %v", bytes.ToLower(encoded), lower, err)
+		}
+
+		fixed, err := EncodeFixed(b, width(len(b)))
+
+		if err != nil {
+			t.Fatalf("EncodeFixed(%x, %v): %v", b, width(len(b)), err)
+		}
+
+		if len(fixed) != width(len(b)) {
+			t.Fatalf("EncodeFixed(%x) has length %v, want %v", b, len(fixed), width(len(b)))
+		}
+
+		unfixed, err := DecodeFixed(fixed, len(b))
+
+		if err != nil {
+			t.Fatalf("DecodeFixed(%q, %v): %v", fixed, len(b), err)
+		}
+
+		if !bytes.Equal(unfixed, b) {
+			t.Fatalf("DecodeFixed(EncodeFixed(%x)) = %x", b, unfixed)
+		}
+	})
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		src  []byte
+		want string
+	}{
+		{[]byte{}, ""},
+		{[]byte{0}, "0"},
+		{[]byte{35}, "Z"},
+		{[]byte{36}, "10"},
+		{[]byte{0x01, 0x00}, "74"},
+		{[]byte{0xff, 0xff, 0xff, 0xff}, "1Z141Z3"},
+	}
+
+	for _, test := range tests {
+		if got := EncodeToString(test.src); got != test.want {
+			t.Errorf("Encode(%x) = %q, want %q", test.src, got, test.want)
+		}
+	}
+}
+
+func TestCorruptInputError(t *testing.T) {
+	tests := []struct {
+		src    string
+		offset CorruptInputError
+	}{
+		{"-", 0},
+		{"12-3", 2},
+		{"ABC ", 3},
+		{"zz\x00", 2},
+		{"1Z\xff", 2},
+	}
+
+	for _, test := range tests {
+		_, err := DecodeString(test.src)
+		var corrupt CorruptInputError
+
+		if !errors.As(err, &corrupt) || (corrupt != test.offset) {
+			t.Errorf("Decode(%q) = %v, want CorruptInputError(%v)", test.src, err, test.offset)
+		}
+
+		if _, err := DecodeFixed([]byte(test.src), 8); !errors.As(err, &corrupt) {
+			t.Errorf("DecodeFixed(%q) = %v, want CorruptInputError", test.src, err)
+		}
+	}
+}
+
+func TestSizeError(t *testing.T) {
+	if _, err := EncodeFixed([]byte{36}, 1); !isSizeError(err, 2, 1) {
+		t.Errorf("EncodeFixed(36, 1) = %v, want SizeError", err)
+	}
+
+	if _, err := DecodeFixed([]byte("ZZZZ"), 2); !isSizeError(err, 3, 2) {
+		t.Errorf("DecodeFixed(ZZZZ, 2) = %v, want SizeError", err)
+	}
+
+	fixed, err := EncodeFixed([]byte{}, 3)
+
+	if (err != nil) || (string(fixed) != "000") {
+		t.Errorf("EncodeFixed(empty, 3) = %q, %v", fixed, err)
+	}
+}
+
+func isSizeError(err error, length, width int) bool {
+	var size *SizeError
+	return errors.As(err, &size) && (size.Length == length) && (size.Width == width)
+}
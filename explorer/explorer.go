@@ -0,0 +1,380 @@
+package explorer
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/kookehs/watchmen/core"
+	"github.com/kookehs/watchmen/primitives"
+	"github.com/kookehs/watchmen/websocket"
+)
+
+// EventBufferSize is the number of events buffered for each WebSocket subscriber.
+var EventBufferSize int = 256
+
+// Explorer serves server-rendered HTML pages describing the state of a Node.
+type Explorer struct {
+	Node      *core.Node
+	mux       *http.ServeMux
+	templates *template.Template
+}
+
+// NewExplorer creates and initializes an Explorer for the given Node.
+func NewExplorer(node *core.Node) *Explorer {
+	explorer := &Explorer{
+		Node:      node,
+		mux:       http.NewServeMux(),
+		templates: template.Must(template.New("explorer").Parse(templates)),
+	}
+
+	explorer.mux.HandleFunc("/", explorer.handleIndex)
+	explorer.mux.HandleFunc("/accounts/", explorer.handleAccount)
+	explorer.mux.HandleFunc("/delegates", explorer.handleDelegates)
+	explorer.mux.HandleFunc("/events", explorer.handleEvents)
+	explorer.mux.HandleFunc("/search", explorer.handleSearch)
+	return explorer
+}
+
+// ListenAndServe serves the Explorer on the given address.
+func (e *Explorer) ListenAndServe(addr string) error {
+	return http.ListenAndServe(addr, e)
+}
+
+// ServeHTTP dispatches the request to the handler of the matching page.
+func (e *Explorer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	e.mux.ServeHTTP(w, r)
+}
+
+// AccountView contains the fields of an Account displayed by the Explorer.
+type AccountView struct {
+	Balance  string
+	Blocks   int
+	Delegate bool
+	IBAN     string
+	Username string
+}
+
+// BlockView contains the fields of a Block displayed by the Explorer.
+type BlockView struct {
+	Balance      string
+	Cosignatures int
+	Hash         string
+	Index        int
+	Previous     string
+	Signature    string
+	Type         string
+	Witness      string
+}
+
+// DelegateView contains the fields of a Delegate displayed by the Explorer.
+type DelegateView struct {
+	Forged   uint64
+	IBAN     string
+	Missed   uint64
+	Rank     int
+	Share    float64
+	Username string
+	Weight   string
+}
+
+// handleAccount renders the chain of the account identified by IBAN or username.
+func (e *Explorer) handleAccount(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/accounts/")
+	e.Node.RLock()
+	account := e.lookup(id)
+
+	if account == nil {
+		e.Node.RUnlock()
+		http.NotFound(w, r)
+		return
+	}
+
+	blocks := e.Node.Ledger.Blocks[account.IBAN.String()]
+	views := make([]BlockView, 0, len(blocks))
+
+	// Newest blocks are shown first.
+	for i := len(blocks) - 1; i >= 0; i-- {
+		views = append(views, makeBlockView(i, blocks[i]))
+	}
+
+	data := struct {
+		Account AccountView
+		Blocks  []BlockView
+	}{
+		Account: e.makeAccountView(account),
+		Blocks:  views,
+	}
+
+	e.Node.RUnlock()
+	e.render(w, "account", data)
+}
+
+// handleDelegates renders the forgers of the current Round and the ranking of all delegates.
+func (e *Explorer) handleDelegates(w http.ResponseWriter, r *http.Request) {
+	e.Node.RLock()
+	dpos := e.Node.DPoS
+	forgers := make([]DelegateView, 0)
+	current := ""
+
+	if dpos.Round != nil {
+		forgers = e.makeDelegateViews(dpos.Round.Forgers)
+
+		if (dpos.Round.Index >= 0) && (dpos.Round.Index < len(dpos.Round.Forgers)) {
+			current = dpos.Round.Forgers[dpos.Round.Index].Account.IBAN.String()
+		}
+	}
+
+	data := struct {
+		Current   string
+		Delegates []DelegateView
+		Forgers   []DelegateView
+		Rounds    uint64
+	}{
+		Current:   current,
+		Delegates: e.makeDelegateViews(dpos.Delegates),
+		Forgers:   forgers,
+		Rounds:    dpos.Rounds,
+	}
+
+	e.Node.RUnlock()
+	e.render(w, "delegates", data)
+}
+
+// handleEvents streams the events of the Node over a WebSocket as JSON text messages.
+// The types query parameter limits the stream to a comma separated list of event types.
+func (e *Explorer) handleEvents(w http.ResponseWriter, r *http.Request) {
+	if e.Node.Events == nil {
+		http.Error(w, "Events are not available", http.StatusServiceUnavailable)
+		return
+	}
+
+	types := make([]core.EventType, 0)
+
+	if query := r.URL.Query().Get("types"); query != "" {
+		for _, name := range strings.Split(query, ",") {
+			t, err := core.ParseEventType(strings.TrimSpace(name))
+
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			types = append(types, t)
+		}
+	}
+
+	conn, err := websocket.Upgrade(w, r)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	defer conn.Close()
+	subscription := e.Node.Events.Subscribe(EventBufferSize, types...)
+	defer subscription.Close()
+
+	// Read until the client closes the connection.
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case event, ok := <-subscription.C:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-done:
+			return
+		}
+	}
+}
+
+// handleIndex renders the list of accounts sorted by username.
+func (e *Explorer) handleIndex(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+
+	e.Node.RLock()
+	ledger := e.Node.Ledger
+	accounts := make([]AccountView, 0, len(ledger.Accounts))
+
+	for _, account := range ledger.Accounts {
+		accounts = append(accounts, e.makeAccountView(account))
+	}
+
+	sort.Slice(accounts, func(i, j int) bool {
+		if accounts[i].Username != accounts[j].Username {
+			return accounts[i].Username < accounts[j].Username
+		}
+
+		return accounts[i].IBAN < accounts[j].IBAN
+	})
+
+	data := struct {
+		Accounts    []AccountView
+		Burned      string
+		Circulating string
+		Supply      string
+	}{
+		Accounts:    accounts,
+		Burned:      ledger.Burned.String(),
+		Circulating: ledger.Circulating().String(),
+		Supply:      ledger.Supply().String(),
+	}
+
+	e.Node.RUnlock()
+	e.render(w, "index", data)
+}
+
+// handleSearch redirects to the account matching the query.
+func (e *Explorer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	e.Node.RLock()
+	account := e.lookup(r.URL.Query().Get("q"))
+	e.Node.RUnlock()
+
+	if account == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	http.Redirect(w, r, "/accounts/"+url.PathEscape(account.IBAN.String()), http.StatusFound)
+}
+
+// lookup returns the Account identified by the given IBAN or username.
+// The caller must hold the read lock of the Node.
+func (e *Explorer) lookup(id string) *core.Account {
+	ledger := e.Node.Ledger
+	id = strings.TrimSpace(id)
+
+	if iban, err := primitives.ParseIBAN(id); err == nil {
+		if account, exist := ledger.Accounts[iban.String()]; exist {
+			return account
+		}
+	}
+
+	if iban, exist := ledger.Users.IBAN(strings.ToLower(id)); exist {
+		return ledger.Accounts[iban.String()]
+	}
+
+	return nil
+}
+
+// makeAccountView returns the AccountView of the given Account.
+func (e *Explorer) makeAccountView(account *core.Account) AccountView {
+	ledger := e.Node.Ledger
+	view := AccountView{
+		Balance:  "0",
+		Blocks:   len(ledger.Blocks[account.IBAN.String()]),
+		Delegate: account.Delegate,
+		IBAN:     account.IBAN.String(),
+		Username: ledger.Username(account.IBAN),
+	}
+
+	if latest := ledger.LatestBlock(account.IBAN); latest != nil {
+		view.Balance = latest.Balance().String()
+	}
+
+	return view
+}
+
+// makeDelegateViews returns the DelegateViews of the given delegates ranked in order.
+func (e *Explorer) makeDelegateViews(delegates core.Delegates) []DelegateView {
+	views := make([]DelegateView, 0, len(delegates))
+
+	for i, delegate := range delegates {
+		views = append(views, DelegateView{
+			Forged:   delegate.Account.Forged,
+			IBAN:     delegate.Account.IBAN.String(),
+			Missed:   delegate.Account.Missed,
+			Rank:     i + 1,
+			Share:    delegate.Account.Share,
+			Username: e.Node.Ledger.Username(delegate.Account.IBAN),
+			Weight:   delegate.Weight.String(),
+		})
+	}
+
+	return views
+}
+
+// render executes the named template logging any error.
+func (e *Explorer) render(w http.ResponseWriter, name string, data interface{}) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+	if err := e.templates.ExecuteTemplate(w, name, data); err != nil {
+		log.Println(err)
+	}
+}
+
+// makeBlockView returns the BlockView of the given block at the given index.
+func makeBlockView(index int, block primitives.Block) BlockView {
+	view := BlockView{
+		Balance:  block.Balance().String(),
+		Index:    index,
+		Previous: block.Previous().String(),
+		Type:     block.Type().String(),
+	}
+
+	if hash, err := block.Hash(); err == nil {
+		view.Hash = hash.String()
+	}
+
+	// Signatures are not exposed by the Block interface.
+	var cosignatures []primitives.Signature
+	var signature, witness primitives.Signature
+
+	switch b := block.(type) {
+	case *primitives.ChangeBlock:
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *primitives.DelegateBlock:
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *primitives.NameBlock:
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *primitives.OpenBlock:
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *primitives.ReceiveBlock:
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *primitives.RotateBlock:
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *primitives.SendBlock:
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	}
+
+	view.Cosignatures = len(cosignatures)
+	view.Signature = hex.EncodeToString(signature.Bytes())
+	view.Witness = hex.EncodeToString(witness.Bytes())
+	return view
+}
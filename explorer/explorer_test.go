@@ -0,0 +1,132 @@
+package explorer
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kookehs/watchmen/core"
+	"github.com/kookehs/watchmen/primitives"
+)
+
+type status struct{}
+
+func (status) Available(string) bool {
+	return true
+}
+
+func newTestNode(t *testing.T) (*core.Node, *core.Account) {
+	t.Helper()
+	ledger := core.NewLedger()
+	dpos := core.NewDPoS()
+	node := core.NewNode(dpos, ledger, status{})
+	genesis, err := node.OpenGenesisAccount("genesis")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node.OpenGenesisDelegates(genesis)
+	return node, genesis
+}
+
+func get(t *testing.T, client *http.Client, url string) (int, string) {
+	t.Helper()
+	response, err := client.Get(url)
+
+	if err != nil {
+		t.Error(err)
+		return 0, ""
+	}
+
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+
+	if err != nil {
+		t.Error(err)
+	}
+
+	return response.StatusCode, string(body)
+}
+
+func TestPages(t *testing.T) {
+	node, genesis := newTestNode(t)
+	server := httptest.NewServer(NewExplorer(node))
+	defer server.Close()
+
+	pages := []struct {
+		path string
+		want string
+	}{
+		{"/", "genesis_1"},
+		{"/accounts/genesis", genesis.IBAN.String()},
+		{"/accounts/" + genesis.IBAN.String(), genesis.IBAN.String()},
+		{"/delegates", "genesis_1"},
+	}
+
+	for _, page := range pages {
+		code, body := get(t, server.Client(), server.URL+page.path)
+
+		if code != http.StatusOK {
+			t.Errorf("GET %v = %v", page.path, code)
+		}
+
+		if !strings.Contains(body, page.want) {
+			t.Errorf("GET %v does not contain %v", page.path, page.want)
+		}
+	}
+
+	if code, _ := get(t, server.Client(), server.URL+"/accounts/nobody"); code != http.StatusNotFound {
+		t.Errorf("GET /accounts/nobody = %v, want %v", code, http.StatusNotFound)
+	}
+}
+
+// TestConcurrentProcess renders pages while requests are processed.
+// Run with -race to detect unsynchronized reads of the Ledger and DPoS.
+func TestConcurrentProcess(t *testing.T) {
+	node, genesis := newTestNode(t)
+	server := httptest.NewServer(NewExplorer(node))
+	defer server.Close()
+
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	for _, path := range []string{"/", "/accounts/genesis", "/delegates", "/search?q=genesis_2"} {
+		wg.Add(1)
+
+		go func(path string) {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if code, _ := get(t, server.Client(), server.URL+path); code != http.StatusOK {
+					t.Errorf("GET %v = %v", path, code)
+					return
+				}
+			}
+		}(path)
+	}
+
+	for i := 0; i < 20; i++ {
+		account, err := node.OpenAccount("user" + string(rune('a'+i)))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := node.Transfer(primitives.NewAmount(1), account.IBAN, genesis.IBAN); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,91 @@
+package explorer
+
+// templates contains the HTML pages rendered by the Explorer.
+const templates = `
+{{define "header"}}<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Watchmen Explorer</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; }
+td, th { border: 1px solid #ccc; padding: 0.25em 0.5em; text-align: left; }
+.hash { font-family: monospace; font-size: 0.8em; word-break: break-all; }
+</style>
+</head>
+<body>
+<nav>
+<a href="/">Accounts</a> | <a href="/delegates">Delegates</a>
+<form action="/search" method="get" style="display: inline">
+<input type="text" name="q" placeholder="IBAN or username">
+<input type="submit" value="Search">
+</form>
+</nav>
+{{end}}
+
+{{define "footer"}}</body>
+</html>
+{{end}}
+
+{{define "index"}}{{template "header"}}
+<h1>Accounts</h1>
+<p>Supply {{.Supply}} | Circulating {{.Circulating}} | Burned {{.Burned}}</p>
+<table>
+<tr><th>Username</th><th>IBAN</th><th>Balance</th><th>Blocks</th><th>Delegate</th></tr>
+{{range .Accounts}}<tr>
+<td>{{.Username}}</td>
+<td class="hash"><a href="/accounts/{{.IBAN}}">{{.IBAN}}</a></td>
+<td>{{.Balance}}</td>
+<td>{{.Blocks}}</td>
+<td>{{if .Delegate}}Yes{{end}}</td>
+</tr>{{end}}
+</table>
+{{template "footer"}}{{end}}
+
+{{define "account"}}{{template "header"}}
+<h1>{{with .Account.Username}}{{.}}{{else}}Account{{end}}</h1>
+<p class="hash">{{.Account.IBAN}}</p>
+<p>Balance {{.Account.Balance}} | Blocks {{.Account.Blocks}}{{if .Account.Delegate}} | Delegate{{end}}</p>
+<table>
+<tr><th>#</th><th>Type</th><th>Balance</th><th>Hash</th><th>Previous</th><th>Signature</th><th>Witness</th></tr>
+{{range .Blocks}}<tr>
+<td>{{.Index}}</td>
+<td>{{.Type}}</td>
+<td>{{.Balance}}</td>
+<td class="hash">{{.Hash}}</td>
+<td class="hash">{{.Previous}}</td>
+<td class="hash">{{if .Cosignatures}}{{.Cosignatures}} cosignatures{{else}}{{.Signature}}{{end}}</td>
+<td class="hash">{{.Witness}}</td>
+</tr>{{end}}
+</table>
+{{template "footer"}}{{end}}
+
+{{define "delegates"}}{{template "header"}}
+<h1>Round {{.Rounds}}</h1>
+<table>
+<tr><th>#</th><th>Forger</th><th>IBAN</th><th>Weight</th><th>Forged</th><th>Missed</th></tr>
+{{range .Forgers}}<tr>
+<td>{{.Rank}}{{if eq .IBAN $.Current}} (next){{end}}</td>
+<td>{{.Username}}</td>
+<td class="hash"><a href="/accounts/{{.IBAN}}">{{.IBAN}}</a></td>
+<td>{{.Weight}}</td>
+<td>{{.Forged}}</td>
+<td>{{.Missed}}</td>
+</tr>{{end}}
+</table>
+<h1>Delegates</h1>
+<table>
+<tr><th>Rank</th><th>Delegate</th><th>IBAN</th><th>Weight</th><th>Share</th><th>Forged</th><th>Missed</th></tr>
+{{range .Delegates}}<tr>
+<td>{{.Rank}}</td>
+<td>{{.Username}}</td>
+<td class="hash"><a href="/accounts/{{.IBAN}}">{{.IBAN}}</a></td>
+<td>{{.Weight}}</td>
+<td>{{.Share}}%</td>
+<td>{{.Forged}}</td>
+<td>{{.Missed}}</td>
+</tr>{{end}}
+</table>
+{{template "footer"}}{{end}}
+`
@@ -2,6 +2,7 @@ package primitives
 
 import (
 	"encoding/gob"
+	"encoding/hex"
 	"encoding/json"
 	"io"
 
@@ -30,6 +31,11 @@ func (bban *BBAN) SetBytes(b []byte) {
 	copy(bban[BBANSize-len(b):], b)
 }
 
+// Address returns the Address of a BBAN using the direct ICAP encoding.
+func (bban *BBAN) Address() (Address, error) {
+	return decodeICAPAddress(bban.String())
+}
+
 // Deserialize decodes byte data encoded by gob.
 func (bban *BBAN) Deserialize(r io.Reader) error {
 	decoder := gob.NewDecoder(r)
@@ -60,24 +66,22 @@ func (bban *BBAN) String() string {
 }
 
 // BBANFromHex generates a BBAN from the given bytes representing a hex number.
+// Returns nil if the number cannot be represented with the direct ICAP encoding.
 func BBANFromHex(b []byte) []byte {
 	if (len(b) == 42) && (b[0] == '0') && (b[1] == 'x') {
-		b[2] = '0'
-		b[3] = 'x'
-		b = b[2:]
-		encoded := base36.Encode(b)
+		decoded := make([]byte, hex.DecodedLen(len(b)-2))
 
-		if padding := IBANSize - len(encoded); padding > 0 {
-			bban := make([]byte, IBANSize)
+		if _, err := hex.Decode(decoded, b[2:]); err != nil {
+			return nil
+		}
 
-			for i := 0; i < padding; i++ {
-				bban = append(bban, '0')
-			}
+		bban, err := base36.EncodeFixed(decoded, BBANSize)
 
-			bban = append(bban, encoded...)
-			return bban
+		if err != nil {
+			return nil
 		}
 
+		return bban
 	}
 
 	return nil
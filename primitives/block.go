@@ -2,7 +2,6 @@ package primitives
 
 import (
 	"bytes"
-	"crypto/ecdsa"
 	"crypto/sha256"
 	"encoding/gob"
 	"encoding/json"
@@ -11,40 +10,54 @@ import (
 	"github.com/kookehs/watchmen/crypto"
 )
 
-// Register various types to allow encoding.
+// Register various types to allow encoding and decoding.
 func init() {
 	amount := NewAmount(0)
 	hash := BlockHashZero
 	iban := IBAN{}
 	gob.Register(NewChangeBlock(amount, []IBAN{}, hash))
 	gob.Register(NewDelegateBlock(amount, hash, 0))
-	gob.Register(NewOpenBlock(amount, iban))
+	gob.Register(NewNameBlock(amount, RegisterName, "", iban, hash))
+	gob.Register(NewOpenBlock(amount, iban, nil))
 	gob.Register(NewReceiveBlock(amount, hash, hash))
+	gob.Register(NewRotateBlock(amount, PublicKey{}, Signature{}, hash))
 	gob.Register(NewSendBlock(amount, iban, hash))
+
+	RegisterBlockType(Change, func() Block { return &ChangeBlock{} })
+	RegisterBlockType(Delegate, func() Block { return &DelegateBlock{} })
+	RegisterBlockType(Name, func() Block { return &NameBlock{} })
+	RegisterBlockType(Open, func() Block { return &OpenBlock{} })
+	RegisterBlockType(Receive, func() Block { return &ReceiveBlock{} })
+	RegisterBlockType(Rotate, func() Block { return &RotateBlock{} })
+	RegisterBlockType(Send, func() Block { return &SendBlock{} })
 }
 
 // Block represents the common elements shared between various types.
 type Block interface {
 	// Block
 	Balance() Amount
+	Cosign(crypto.Signer) error
 	Delegates() []IBAN
 	Hash() (BlockHash, error)
 	Previous() BlockHash
 	Root() BlockHash
 	Share() float64
-	Sign(*ecdsa.PrivateKey) error
-	SignWitness(*ecdsa.PrivateKey) error
+	Sign(crypto.Signer) error
+	SignWitness(crypto.Signer) error
 	Source() BlockHash
 	Timestamp() int64
 	Type() BlockType
-	Verify(*ecdsa.PublicKey) (bool, error)
-	VerifyWitness(*ecdsa.PublicKey) (bool, error)
+	Verify(crypto.Verifier) (bool, error)
+	VerifyPolicy(*Policy) (bool, error)
+	VerifyWitness(crypto.Verifier) (bool, error)
 
 	// Deserialization
 	Deserialize(io.Reader) error
 	DeserializeJSON(io.Reader) error
+	UnmarshalProto([]byte) error
 
 	// Serialization
+	MarshalProto() ([]byte, error)
 	Serialize(io.Writer) error
 	SerializeJSON(io.Writer) error
 
@@ -55,9 +68,10 @@ type Block interface {
 
 // ChangeBlock represents a change in delegates.
 type ChangeBlock struct {
-	Hashables ChangeHashables `json:"hashables"`
-	Signature Signature       `json:"signature"`
-	Witness   Signature       `json:"witness"`
+	Cosignatures []Signature     `json:"cosignatures,omitempty"`
+	Hashables    ChangeHashables `json:"hashables"`
+	Signature    Signature       `json:"signature"`
+	Witness      Signature       `json:"witness"`
 }
 
 // NewChangeBlock creates and initializes a ChangeBlock from the given arguments.
@@ -72,6 +86,24 @@ func (cb *ChangeBlock) Balance() Amount {
 	return cb.Hashables.Balance
 }
 
+// Cosign adds a signature made with the given private key of a multi-signature Policy.
+func (cb *ChangeBlock) Cosign(priv crypto.Signer) error {
+	hash, err := cb.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	cb.Cosignatures = append(cb.Cosignatures, signature)
+	return nil
+}
+
 // Delegates returns the delegates associated with this block.
 func (cb *ChangeBlock) Delegates() []IBAN {
 	return cb.Hashables.Delegates
@@ -104,38 +136,38 @@ func (cb *ChangeBlock) Share() float64 {
 }
 
 // Sign signs the block with the given private key.
-func (cb *ChangeBlock) Sign(priv *ecdsa.PrivateKey) error {
+func (cb *ChangeBlock) Sign(priv crypto.Signer) error {
 	hash, err := cb.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	cb.Signature = MakeSignature(r, s)
+	cb.Signature = signature
 	return nil
 }
 
 // SignWitness signs the block with the given private key of a delegate.
-func (cb *ChangeBlock) SignWitness(priv *ecdsa.PrivateKey) error {
+func (cb *ChangeBlock) SignWitness(priv crypto.Signer) error {
 	hash, err := cb.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	cb.Witness = MakeSignature(r, s)
+	cb.Witness = signature
 	return nil
 }
 
@@ -155,25 +187,36 @@ func (cb *ChangeBlock) Type() BlockType {
 }
 
 // Verify verifies whether this block was signed by the given public key owner.
-func (cb *ChangeBlock) Verify(pub *ecdsa.PublicKey) (bool, error) {
+func (cb *ChangeBlock) Verify(pub crypto.Verifier) (bool, error) {
+	hash, err := cb.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return cb.Signature.Verify(hash[:], pub), nil
+}
+
+// VerifyPolicy verifies whether this block was signed by enough keys of the given Policy.
+func (cb *ChangeBlock) VerifyPolicy(policy *Policy) (bool, error) {
 	hash, err := cb.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, cb.Signature.R, cb.Signature.S), nil
+	return policy.Verify(hash[:], cb.Cosignatures), nil
 }
 
 // VerifyWitness verifies whether this block was signed by the given public key of a delegate.
-func (cb *ChangeBlock) VerifyWitness(pub *ecdsa.PublicKey) (bool, error) {
+func (cb *ChangeBlock) VerifyWitness(pub crypto.Verifier) (bool, error) {
 	hash, err := cb.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, cb.Witness.R, cb.Witness.S), nil
+	return cb.Witness.Verify(hash[:], pub), nil
 }
 
 // Deserialize decodes byte data encoded by gob.
@@ -184,8 +227,7 @@ func (cb *ChangeBlock) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (cb *ChangeBlock) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(cb)
+	return decodeBalanceJSON(r, cb, &cb.Hashables.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -218,9 +260,10 @@ func (cb *ChangeBlock) ToJSON() (string, error) {
 
 // DelegateBlock represents a change in delegates.
 type DelegateBlock struct {
-	Hashables DelegateHashables `json:"hashables"`
-	Signature Signature         `json:"signature"`
-	Witness   Signature         `json:"witness"`
+	Cosignatures []Signature       `json:"cosignatures,omitempty"`
+	Hashables    DelegateHashables `json:"hashables"`
+	Signature    Signature         `json:"signature"`
+	Witness      Signature         `json:"witness"`
 }
 
 // NewDelegateBlock creates and initializes a DelegateBlock from the given arguments.
@@ -235,6 +278,24 @@ func (db *DelegateBlock) Balance() Amount {
 	return db.Hashables.Balance
 }
 
+// Cosign adds a signature made with the given private key of a multi-signature Policy.
+func (db *DelegateBlock) Cosign(priv crypto.Signer) error {
+	hash, err := db.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	db.Cosignatures = append(db.Cosignatures, signature)
+	return nil
+}
+
 // Delegates returns the delegates associated with this block.
 func (db *DelegateBlock) Delegates() []IBAN {
 	return nil
@@ -267,38 +328,38 @@ func (db *DelegateBlock) Share() float64 {
 }
 
 // Sign signs the block with the given private key.
-func (db *DelegateBlock) Sign(priv *ecdsa.PrivateKey) error {
+func (db *DelegateBlock) Sign(priv crypto.Signer) error {
 	hash, err := db.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	db.Signature = MakeSignature(r, s)
+	db.Signature = signature
 	return nil
 }
 
 // SignWitness signs the block with the given private key of a delegate.
-func (db *DelegateBlock) SignWitness(priv *ecdsa.PrivateKey) error {
+func (db *DelegateBlock) SignWitness(priv crypto.Signer) error {
 	hash, err := db.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	db.Witness = MakeSignature(r, s)
+	db.Witness = signature
 	return nil
 }
 
@@ -318,25 +379,36 @@ func (db *DelegateBlock) Type() BlockType {
 }
 
 // Verify verifies whether this block was signed by the given public key owner.
-func (db *DelegateBlock) Verify(pub *ecdsa.PublicKey) (bool, error) {
+func (db *DelegateBlock) Verify(pub crypto.Verifier) (bool, error) {
 	hash, err := db.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, db.Signature.R, db.Signature.S), nil
+	return db.Signature.Verify(hash[:], pub), nil
+}
+
+// VerifyPolicy verifies whether this block was signed by enough keys of the given Policy.
+func (db *DelegateBlock) VerifyPolicy(policy *Policy) (bool, error) {
+	hash, err := db.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return policy.Verify(hash[:], db.Cosignatures), nil
 }
 
 // VerifyWitness verifies whether this block was signed by the given public key of a delegate.
-func (db *DelegateBlock) VerifyWitness(pub *ecdsa.PublicKey) (bool, error) {
+func (db *DelegateBlock) VerifyWitness(pub crypto.Verifier) (bool, error) {
 	hash, err := db.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, db.Witness.R, db.Witness.S), nil
+	return db.Witness.Verify(hash[:], pub), nil
 }
 
 // Deserialize decodes byte data encoded by gob.
@@ -347,8 +419,7 @@ func (db *DelegateBlock) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (db *DelegateBlock) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(db)
+	return decodeBalanceJSON(r, db, &db.Hashables.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -379,25 +450,254 @@ func (db *DelegateBlock) ToJSON() (string, error) {
 	return string(bytes), nil
 }
 
+// NameBlock represents the registration, release or transfer of a username.
+type NameBlock struct {
+	Cosignatures []Signature   `json:"cosignatures,omitempty"`
+	Hashables    NameHashables `json:"hashables"`
+	Signature    Signature     `json:"signature"`
+	Witness      Signature     `json:"witness"`
+}
+
+// NewNameBlock creates and initializes a NameBlock from the given arguments.
+func NewNameBlock(amt Amount, action NameAction, name string, dst IBAN, prev BlockHash) *NameBlock {
+	return &NameBlock{
+		Hashables: MakeNameHashables(amt, action, name, dst, prev),
+	}
+}
+
+// Action returns the name action associated with this block.
+func (nb *NameBlock) Action() NameAction {
+	return nb.Hashables.Action
+}
+
+// Balance returns the balance associated with this block.
+func (nb *NameBlock) Balance() Amount {
+	return nb.Hashables.Balance
+}
+
+// Cosign adds a signature made with the given private key of a multi-signature Policy.
+func (nb *NameBlock) Cosign(priv crypto.Signer) error {
+	hash, err := nb.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	nb.Cosignatures = append(nb.Cosignatures, signature)
+	return nil
+}
+
+// Delegates returns the delegates associated with this block.
+func (nb *NameBlock) Delegates() []IBAN {
+	return nil
+}
+
+// Hash returns the SHA256 hash of the serialized bytes of Hashables.
+func (nb *NameBlock) Hash() (BlockHash, error) {
+	var buffer bytes.Buffer
+
+	if err := nb.Hashables.Serialize(&buffer); err != nil {
+		return BlockHashZero, err
+	}
+
+	return sha256.Sum256(buffer.Bytes()), nil
+}
+
+// Previous returns the previous hash associated with this block.
+func (nb *NameBlock) Previous() BlockHash {
+	return nb.Hashables.Previous
+}
+
+// Root returns the previous hash associated with this block.
+func (nb *NameBlock) Root() BlockHash {
+	return nb.Hashables.Previous
+}
+
+// Name returns the username associated with this block.
+func (nb *NameBlock) Name() string {
+	return nb.Hashables.Name
+}
+
+// Share returns the percentage of rewards delegates share.
+func (nb *NameBlock) Share() float64 {
+	return -1
+}
+
+// Sign signs the block with the given private key.
+func (nb *NameBlock) Sign(priv crypto.Signer) error {
+	hash, err := nb.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	nb.Signature = signature
+	return nil
+}
+
+// SignWitness signs the block with the given private key of a delegate.
+func (nb *NameBlock) SignWitness(priv crypto.Signer) error {
+	hash, err := nb.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	nb.Witness = signature
+	return nil
+}
+
+// Source returns the source hash associated with this block.
+func (nb *NameBlock) Source() BlockHash {
+	return BlockHashZero
+}
+
+// Timestamp returns the timestamp of when the block was created.
+func (nb *NameBlock) Timestamp() int64 {
+	return nb.Hashables.Timestamp
+}
+
+// Type returns the type of this block.
+func (nb *NameBlock) Type() BlockType {
+	return Name
+}
+
+// Verify verifies whether this block was signed by the given public key owner.
+func (nb *NameBlock) Verify(pub crypto.Verifier) (bool, error) {
+	hash, err := nb.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return nb.Signature.Verify(hash[:], pub), nil
+}
+
+// VerifyPolicy verifies whether this block was signed by enough keys of the given Policy.
+func (nb *NameBlock) VerifyPolicy(policy *Policy) (bool, error) {
+	hash, err := nb.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return policy.Verify(hash[:], nb.Cosignatures), nil
+}
+
+// VerifyWitness verifies whether this block was signed by the given public key of a delegate.
+func (nb *NameBlock) VerifyWitness(pub crypto.Verifier) (bool, error) {
+	hash, err := nb.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return nb.Witness.Verify(hash[:], pub), nil
+}
+
+// Deserialize decodes byte data encoded by gob.
+func (nb *NameBlock) Deserialize(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	return decoder.Decode(nb)
+}
+
+// DeserializeJSON decodes JSON data.
+func (nb *NameBlock) DeserializeJSON(r io.Reader) error {
+	return decodeBalanceJSON(r, nb, &nb.Hashables.Balance)
+}
+
+// Serialize encodes to byte data using gob.
+func (nb *NameBlock) Serialize(w io.Writer) error {
+	encoder := gob.NewEncoder(w)
+	return encoder.Encode(nb)
+}
+
+// SerializeJSON encodes to JSON data.
+func (nb *NameBlock) SerializeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(nb)
+}
+
+// String returns a JSON encoded string.
+func (nb *NameBlock) String() (string, error) {
+	return nb.ToJSON()
+}
+
+// ToJSON returns a JSON encoded string.
+func (nb *NameBlock) ToJSON() (string, error) {
+	bytes, err := json.Marshal(nb)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
 // OpenBlock represents a openining of an account.
 type OpenBlock struct {
-	Hashables OpenHashables `json:"hashables"`
-	Signature Signature     `json:"signature"`
-	Witness   Signature     `json:"witness"`
+	Cosignatures []Signature   `json:"cosignatures,omitempty"`
+	Hashables    OpenHashables `json:"hashables"`
+	Signature    Signature     `json:"signature"`
+	Witness      Signature     `json:"witness"`
 }
 
 // NewOpenBlock creates and initializes an OpenBlock from the given arguments.
-func NewOpenBlock(amt Amount, iban IBAN) *OpenBlock {
+// The key is recorded so that the account can be verified after rotating keys.
+func NewOpenBlock(amt Amount, iban IBAN, key *PublicKey) *OpenBlock {
 	return &OpenBlock{
-		Hashables: MakeOpenHashables(amt, iban),
+		Hashables: MakeOpenHashables(amt, iban, key),
 	}
 }
 
+// NewMultisigOpenBlock creates and initializes an OpenBlock declaring the given multi-signature Policy.
+func NewMultisigOpenBlock(amt Amount, iban IBAN, policy *Policy) *OpenBlock {
+	block := NewOpenBlock(amt, iban, nil)
+	block.Hashables.Policy = policy
+	return block
+}
+
 // Balance returns the balance associated with this block.
 func (ob *OpenBlock) Balance() Amount {
 	return ob.Hashables.Balance
 }
 
+// Cosign adds a signature made with the given private key of a multi-signature Policy.
+func (ob *OpenBlock) Cosign(priv crypto.Signer) error {
+	hash, err := ob.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	ob.Cosignatures = append(ob.Cosignatures, signature)
+	return nil
+}
+
 // Delegates returns the delegates associated with this block.
 func (ob *OpenBlock) Delegates() []IBAN {
 	return nil
@@ -431,38 +731,38 @@ func (ob *OpenBlock) Share() float64 {
 }
 
 // Sign signs the block with the given private key.
-func (ob *OpenBlock) Sign(priv *ecdsa.PrivateKey) error {
+func (ob *OpenBlock) Sign(priv crypto.Signer) error {
 	hash, err := ob.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	ob.Signature = MakeSignature(r, s)
+	ob.Signature = signature
 	return nil
 }
 
 // SignWitness signs the block with the given private key of a delegate.
-func (ob *OpenBlock) SignWitness(priv *ecdsa.PrivateKey) error {
+func (ob *OpenBlock) SignWitness(priv crypto.Signer) error {
 	hash, err := ob.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	ob.Witness = MakeSignature(r, s)
+	ob.Witness = signature
 	return nil
 }
 
@@ -482,25 +782,36 @@ func (ob *OpenBlock) Type() BlockType {
 }
 
 // Verify verifies whether this block was signed by the given public key owner.
-func (ob *OpenBlock) Verify(pub *ecdsa.PublicKey) (bool, error) {
+func (ob *OpenBlock) Verify(pub crypto.Verifier) (bool, error) {
 	hash, err := ob.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, ob.Signature.R, ob.Signature.S), nil
+	return ob.Signature.Verify(hash[:], pub), nil
+}
+
+// VerifyPolicy verifies whether this block was signed by enough keys of the given Policy.
+func (ob *OpenBlock) VerifyPolicy(policy *Policy) (bool, error) {
+	hash, err := ob.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return policy.Verify(hash[:], ob.Cosignatures), nil
 }
 
 // VerifyWitness verifies whether this block was signed by the given public key of a delegate.
-func (ob *OpenBlock) VerifyWitness(pub *ecdsa.PublicKey) (bool, error) {
+func (ob *OpenBlock) VerifyWitness(pub crypto.Verifier) (bool, error) {
 	hash, err := ob.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, ob.Witness.R, ob.Witness.S), nil
+	return ob.Witness.Verify(hash[:], pub), nil
 }
 
 // Deserialize decodes byte data encoded by gob.
@@ -511,8 +822,7 @@ func (ob *OpenBlock) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (ob *OpenBlock) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(ob)
+	return decodeBalanceJSON(r, ob, &ob.Hashables.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -545,9 +855,10 @@ func (ob *OpenBlock) ToJSON() (string, error) {
 
 // ReceiveBlock represents the receiving end of a send transaction.
 type ReceiveBlock struct {
-	Hashables ReceiveHashables `json:"hashables"`
-	Signature Signature        `json:"signature"`
-	Witness   Signature        `json:"witness"`
+	Cosignatures []Signature      `json:"cosignatures,omitempty"`
+	Hashables    ReceiveHashables `json:"hashables"`
+	Signature    Signature        `json:"signature"`
+	Witness      Signature        `json:"witness"`
 }
 
 // NewReceiveBlock creates and initializes a ReceiveBlock from the given arguments.
@@ -562,6 +873,24 @@ func (rb *ReceiveBlock) Balance() Amount {
 	return rb.Hashables.Balance
 }
 
+// Cosign adds a signature made with the given private key of a multi-signature Policy.
+func (rb *ReceiveBlock) Cosign(priv crypto.Signer) error {
+	hash, err := rb.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	rb.Cosignatures = append(rb.Cosignatures, signature)
+	return nil
+}
+
 // Delegates returns the delegates associated with this block.
 func (rb *ReceiveBlock) Delegates() []IBAN {
 	return nil
@@ -594,38 +923,38 @@ func (rb *ReceiveBlock) Share() float64 {
 }
 
 // Sign signs the block with the given private key.
-func (rb *ReceiveBlock) Sign(priv *ecdsa.PrivateKey) error {
+func (rb *ReceiveBlock) Sign(priv crypto.Signer) error {
 	hash, err := rb.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	rb.Signature = MakeSignature(r, s)
+	rb.Signature = signature
 	return nil
 }
 
 // SignWitness signs the block with the given private key of a delegate.
-func (rb *ReceiveBlock) SignWitness(priv *ecdsa.PrivateKey) error {
+func (rb *ReceiveBlock) SignWitness(priv crypto.Signer) error {
 	hash, err := rb.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	rb.Witness = MakeSignature(r, s)
+	rb.Witness = signature
 	return nil
 }
 
@@ -645,25 +974,36 @@ func (rb *ReceiveBlock) Type() BlockType {
 }
 
 // Verify verifies whether this block was signed by the given public key owner.
-func (rb *ReceiveBlock) Verify(pub *ecdsa.PublicKey) (bool, error) {
+func (rb *ReceiveBlock) Verify(pub crypto.Verifier) (bool, error) {
 	hash, err := rb.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, rb.Signature.R, rb.Signature.S), nil
+	return rb.Signature.Verify(hash[:], pub), nil
+}
+
+// VerifyPolicy verifies whether this block was signed by enough keys of the given Policy.
+func (rb *ReceiveBlock) VerifyPolicy(policy *Policy) (bool, error) {
+	hash, err := rb.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return policy.Verify(hash[:], rb.Cosignatures), nil
 }
 
 // VerifyWitness verifies whether this block was signed by the given public key of a delegate.
-func (rb *ReceiveBlock) VerifyWitness(pub *ecdsa.PublicKey) (bool, error) {
+func (rb *ReceiveBlock) VerifyWitness(pub crypto.Verifier) (bool, error) {
 	hash, err := rb.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, rb.Witness.R, rb.Witness.S), nil
+	return rb.Witness.Verify(hash[:], pub), nil
 }
 
 // Deserialize decodes byte data encoded by gob.
@@ -674,8 +1014,7 @@ func (rb *ReceiveBlock) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (rb *ReceiveBlock) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(rb)
+	return decodeBalanceJSON(r, rb, &rb.Hashables.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -706,11 +1045,227 @@ func (rb *ReceiveBlock) ToJSON() (string, error) {
 	return string(bytes), nil
 }
 
+// RotateBlock represents the authorization of a new key for an account.
+// It is signed by the new key and authorized by the previously authorized key.
+type RotateBlock struct {
+	Cosignatures []Signature     `json:"cosignatures,omitempty"`
+	Hashables    RotateHashables `json:"hashables"`
+	Signature    Signature       `json:"signature"`
+	Witness      Signature       `json:"witness"`
+}
+
+// NewRotateBlock creates and initializes a RotateBlock from the given arguments.
+func NewRotateBlock(amt Amount, key PublicKey, authorization Signature, prev BlockHash) *RotateBlock {
+	return &RotateBlock{
+		Hashables: MakeRotateHashables(amt, key, authorization, prev),
+	}
+}
+
+// RotationHash returns the hash signed by the previously authorized key to authorize the given key.
+func RotationHash(key PublicKey, prev BlockHash) []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString("rotate")
+	buffer.Write(prev[:])
+	buffer.WriteByte(byte(key.Scheme))
+	buffer.Write(key.Bytes)
+	hash := sha256.Sum256(buffer.Bytes())
+	return hash[:]
+}
+
+// Balance returns the balance associated with this block.
+func (rob *RotateBlock) Balance() Amount {
+	return rob.Hashables.Balance
+}
+
+// Cosign adds a signature made with the given private key of a multi-signature Policy.
+func (rob *RotateBlock) Cosign(priv crypto.Signer) error {
+	hash, err := rob.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	rob.Cosignatures = append(rob.Cosignatures, signature)
+	return nil
+}
+
+// Delegates returns the delegates associated with this block.
+func (rob *RotateBlock) Delegates() []IBAN {
+	return nil
+}
+
+// Hash returns the SHA256 hash of the serialized bytes of Hashables.
+func (rob *RotateBlock) Hash() (BlockHash, error) {
+	var buffer bytes.Buffer
+
+	if err := rob.Hashables.Serialize(&buffer); err != nil {
+		return BlockHashZero, err
+	}
+
+	return sha256.Sum256(buffer.Bytes()), nil
+}
+
+// Key returns the public key authorized by this block.
+func (rob *RotateBlock) Key() PublicKey {
+	return rob.Hashables.Key
+}
+
+// Previous returns the previous hash associated with this block.
+func (rob *RotateBlock) Previous() BlockHash {
+	return rob.Hashables.Previous
+}
+
+// Root returns the previous hash associated with this block.
+func (rob *RotateBlock) Root() BlockHash {
+	return rob.Hashables.Previous
+}
+
+// Share returns the percentage of rewards delegates share.
+func (rob *RotateBlock) Share() float64 {
+	return -1
+}
+
+// Sign signs the block with the given private key.
+func (rob *RotateBlock) Sign(priv crypto.Signer) error {
+	hash, err := rob.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	rob.Signature = signature
+	return nil
+}
+
+// SignWitness signs the block with the given private key of a delegate.
+func (rob *RotateBlock) SignWitness(priv crypto.Signer) error {
+	hash, err := rob.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	rob.Witness = signature
+	return nil
+}
+
+// Source returns the source hash associated with this block.
+func (rob *RotateBlock) Source() BlockHash {
+	return BlockHashZero
+}
+
+// Timestamp returns the timestamp of when the block was created.
+func (rob *RotateBlock) Timestamp() int64 {
+	return rob.Hashables.Timestamp
+}
+
+// Type returns the type of this block.
+func (rob *RotateBlock) Type() BlockType {
+	return Rotate
+}
+
+// Verify verifies whether this block was signed by the given public key owner.
+func (rob *RotateBlock) Verify(pub crypto.Verifier) (bool, error) {
+	hash, err := rob.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return rob.Signature.Verify(hash[:], pub), nil
+}
+
+// VerifyAuthorization verifies whether the new key was authorized by the given previously authorized key.
+func (rob *RotateBlock) VerifyAuthorization(pub crypto.Verifier) bool {
+	hash := RotationHash(rob.Hashables.Key, rob.Hashables.Previous)
+	return rob.Hashables.Authorization.Verify(hash, pub)
+}
+
+// VerifyPolicy verifies whether this block was signed by enough keys of the given Policy.
+func (rob *RotateBlock) VerifyPolicy(policy *Policy) (bool, error) {
+	hash, err := rob.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return policy.Verify(hash[:], rob.Cosignatures), nil
+}
+
+// VerifyWitness verifies whether this block was signed by the given public key of a delegate.
+func (rob *RotateBlock) VerifyWitness(pub crypto.Verifier) (bool, error) {
+	hash, err := rob.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return rob.Witness.Verify(hash[:], pub), nil
+}
+
+// Deserialize decodes byte data encoded by gob.
+func (rob *RotateBlock) Deserialize(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	return decoder.Decode(rob)
+}
+
+// DeserializeJSON decodes JSON data.
+func (rob *RotateBlock) DeserializeJSON(r io.Reader) error {
+	return decodeBalanceJSON(r, rob, &rob.Hashables.Balance)
+}
+
+// Serialize encodes to byte data using gob.
+func (rob *RotateBlock) Serialize(w io.Writer) error {
+	encoder := gob.NewEncoder(w)
+	return encoder.Encode(rob)
+}
+
+// SerializeJSON encodes to JSON data.
+func (rob *RotateBlock) SerializeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(rob)
+}
+
+// String returns a json encoded string.
+func (rob *RotateBlock) String() (string, error) {
+	return rob.ToJSON()
+}
+
+// ToJSON returns a JSON encoded string.
+func (rob *RotateBlock) ToJSON() (string, error) {
+	bytes, err := json.Marshal(rob)
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
 // SendBlock represents the sending of a transaction.
 type SendBlock struct {
-	Hashables SendHashables `json:"hashables"`
-	Signature Signature     `json:"signature"`
-	Witness   Signature     `json:"witness"`
+	Cosignatures []Signature   `json:"cosignatures,omitempty"`
+	Hashables    SendHashables `json:"hashables"`
+	Signature    Signature     `json:"signature"`
+	Witness      Signature     `json:"witness"`
 }
 
 // NewSendBlock creates and initializes a SendBlock from the given arguments.
@@ -725,6 +1280,24 @@ func (sb *SendBlock) Balance() Amount {
 	return sb.Hashables.Balance
 }
 
+// Cosign adds a signature made with the given private key of a multi-signature Policy.
+func (sb *SendBlock) Cosign(priv crypto.Signer) error {
+	hash, err := sb.Hash()
+
+	if err != nil {
+		return err
+	}
+
+	signature, err := SignHash(hash[:], priv)
+
+	if err != nil {
+		return err
+	}
+
+	sb.Cosignatures = append(sb.Cosignatures, signature)
+	return nil
+}
+
 // Delegates returns the delegates associated with this block.
 func (sb *SendBlock) Delegates() []IBAN {
 	return nil
@@ -757,38 +1330,38 @@ func (sb *SendBlock) Share() float64 {
 }
 
 // Sign signs the block with the given private key.
-func (sb *SendBlock) Sign(priv *ecdsa.PrivateKey) error {
+func (sb *SendBlock) Sign(priv crypto.Signer) error {
 	hash, err := sb.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	sb.Signature = MakeSignature(r, s)
+	sb.Signature = signature
 	return nil
 }
 
 // SignWitness signs the block with the given private key of a delegate.
-func (sb *SendBlock) SignWitness(priv *ecdsa.PrivateKey) error {
+func (sb *SendBlock) SignWitness(priv crypto.Signer) error {
 	hash, err := sb.Hash()
 
 	if err != nil {
 		return err
 	}
 
-	r, s, err := crypto.Sign(hash[:], priv)
+	signature, err := SignHash(hash[:], priv)
 
 	if err != nil {
 		return err
 	}
 
-	sb.Witness = MakeSignature(r, s)
+	sb.Witness = signature
 	return nil
 }
 
@@ -808,25 +1381,36 @@ func (sb *SendBlock) Type() BlockType {
 }
 
 // Verify verifies whether this block was signed by the given public key owner.
-func (sb *SendBlock) Verify(pub *ecdsa.PublicKey) (bool, error) {
+func (sb *SendBlock) Verify(pub crypto.Verifier) (bool, error) {
+	hash, err := sb.Hash()
+
+	if err != nil {
+		return false, err
+	}
+
+	return sb.Signature.Verify(hash[:], pub), nil
+}
+
+// VerifyPolicy verifies whether this block was signed by enough keys of the given Policy.
+func (sb *SendBlock) VerifyPolicy(policy *Policy) (bool, error) {
 	hash, err := sb.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, sb.Signature.R, sb.Signature.S), nil
+	return policy.Verify(hash[:], sb.Cosignatures), nil
 }
 
 // VerifyWitness verifies whether this block was signed by the given public key of a delegate.
-func (sb *SendBlock) VerifyWitness(pub *ecdsa.PublicKey) (bool, error) {
+func (sb *SendBlock) VerifyWitness(pub crypto.Verifier) (bool, error) {
 	hash, err := sb.Hash()
 
 	if err != nil {
 		return false, err
 	}
 
-	return crypto.Verify(hash[:], pub, sb.Witness.R, sb.Witness.S), nil
+	return sb.Witness.Verify(hash[:], pub), nil
 }
 
 // Deserialize decodes byte data encoded by gob.
@@ -837,8 +1421,7 @@ func (sb *SendBlock) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (sb *SendBlock) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(sb)
+	return decodeBalanceJSON(r, sb, &sb.Hashables.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -0,0 +1,166 @@
+package primitives
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// blockFactories contains the function creating an empty block for each registered BlockType.
+var blockFactories = make(map[BlockType]func() Block)
+
+// NewBlock returns an empty block of the given type to decode into.
+func NewBlock(t BlockType) (Block, error) {
+	factory, exist := blockFactories[t]
+
+	if !exist {
+		return nil, fmt.Errorf("Block type %v is not registered", t)
+	}
+
+	return factory(), nil
+}
+
+// RegisterBlockType registers the function creating empty blocks of the given type for decoding.
+func RegisterBlockType(t BlockType, factory func() Block) {
+	blockFactories[t] = factory
+}
+
+// DecodeBlockJSON decodes a block wrapped in a BlockEnvelope.
+func DecodeBlockJSON(data []byte) (Block, error) {
+	var envelope BlockEnvelope
+
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, err
+	}
+
+	return envelope.Block, nil
+}
+
+// EncodeBlockJSON encodes the given block wrapped in a BlockEnvelope.
+func EncodeBlockJSON(block Block) ([]byte, error) {
+	return json.Marshal(BlockEnvelope{Block: block})
+}
+
+// BlockEnvelope wraps a Block with the name of its type so that it can be decoded
+// without knowing its concrete type beforehand.
+type BlockEnvelope struct {
+	Block Block
+}
+
+// blockEnvelopeJSON is the JSON representation of a BlockEnvelope.
+type blockEnvelopeJSON struct {
+	Block json.RawMessage `json:"block"`
+	Type  string          `json:"type"`
+}
+
+// MarshalJSON encodes the block along with the name of its type.
+func (be BlockEnvelope) MarshalJSON() ([]byte, error) {
+	if be.Block == nil {
+		return []byte("null"), nil
+	}
+
+	block, err := json.Marshal(be.Block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(blockEnvelopeJSON{
+		Block: block,
+		Type:  be.Block.Type().String(),
+	})
+}
+
+// UnmarshalJSON decodes the block into a new block of the registered type.
+func (be *BlockEnvelope) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		be.Block = nil
+		return nil
+	}
+
+	var envelope blockEnvelopeJSON
+
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+
+	if len(envelope.Block) == 0 {
+		return errors.New("Block envelope does not contain a block")
+	}
+
+	t, err := ParseBlockType(envelope.Type)
+
+	if err != nil {
+		return err
+	}
+
+	// The type of the hashables is part of the hash so it must agree with the envelope.
+	var header struct {
+		Hashables struct {
+			Type BlockType `json:"type"`
+		} `json:"hashables"`
+	}
+
+	if err := json.Unmarshal(envelope.Block, &header); err != nil {
+		return err
+	}
+
+	if header.Hashables.Type != t {
+		return fmt.Errorf("Block of type %v does not match envelope type %v", header.Hashables.Type, t)
+	}
+
+	block, err := NewBlock(t)
+
+	if err != nil {
+		return err
+	}
+
+	if err := block.DeserializeJSON(bytes.NewReader(envelope.Block)); err != nil {
+		return err
+	}
+
+	be.Block = block
+	return nil
+}
+
+// Blocks is a list of blocks of any type encoded in JSON as BlockEnvelopes.
+type Blocks []Block
+
+// MarshalJSON encodes every block in a BlockEnvelope.
+func (b Blocks) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+
+	envelopes := make([]BlockEnvelope, 0, len(b))
+
+	for _, block := range b {
+		envelopes = append(envelopes, BlockEnvelope{Block: block})
+	}
+
+	return json.Marshal(envelopes)
+}
+
+// UnmarshalJSON decodes a list of BlockEnvelopes.
+func (b *Blocks) UnmarshalJSON(data []byte) error {
+	var envelopes []BlockEnvelope
+
+	if err := json.Unmarshal(data, &envelopes); err != nil {
+		return err
+	}
+
+	if envelopes == nil {
+		*b = nil
+		return nil
+	}
+
+	blocks := make(Blocks, 0, len(envelopes))
+
+	for _, envelope := range envelopes {
+		blocks = append(blocks, envelope.Block)
+	}
+
+	*b = blocks
+	return nil
+}
@@ -0,0 +1,119 @@
+package primitives
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestBlockJSONRoundTrip(t *testing.T) {
+	for _, test := range testBlocks(t) {
+		data, err := EncodeBlockJSON(test.block)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := DecodeBlockJSON(data)
+
+		if err != nil {
+			t.Fatalf("DecodeBlockJSON of %v: %v", test.block.Type(), err)
+		}
+
+		checkSameBlock(t, decoded, test.block)
+	}
+}
+
+func TestBlocksJSONRoundTrip(t *testing.T) {
+	tests := testBlocks(t)
+	blocks := make(Blocks, 0, len(tests))
+
+	for _, test := range tests {
+		blocks = append(blocks, test.block)
+	}
+
+	data, err := json.Marshal(blocks)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded Blocks
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(decoded) != len(blocks) {
+		t.Fatalf("Decoded %v blocks, want %v", len(decoded), len(blocks))
+	}
+
+	for i := range blocks {
+		checkSameBlock(t, decoded[i], blocks[i])
+	}
+
+	for _, input := range []string{"null", "[]"} {
+		var empty Blocks
+
+		if err := json.Unmarshal([]byte(input), &empty); err != nil {
+			t.Fatalf("Unmarshal(%v): %v", input, err)
+		}
+
+		if data, err := json.Marshal(empty); (err != nil) || (string(data) != input) {
+			t.Errorf("Marshal of %v = %s, %v", input, data, err)
+		}
+	}
+}
+
+func TestBlockEnvelopeTypeMismatch(t *testing.T) {
+	for _, test := range testBlocks(t) {
+		data, err := EncodeBlockJSON(test.block)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var envelope map[string]json.RawMessage
+
+		if err := json.Unmarshal(data, &envelope); err != nil {
+			t.Fatal(err)
+		}
+
+		for bt := Change; bt <= Rotate; bt++ {
+			if bt == test.block.Type() {
+				continue
+			}
+
+			envelope["type"] = json.RawMessage(`"` + bt.String() + `"`)
+			tampered, err := json.Marshal(envelope)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := DecodeBlockJSON(tampered); err == nil {
+				t.Errorf("DecodeBlockJSON of %v in a %v envelope succeeded", test.block.Type(), bt)
+			}
+		}
+	}
+}
+
+func TestBlockEnvelopeErrors(t *testing.T) {
+	inputs := []string{
+		`{"type":"Send"}`,
+		`{"type":"Transfer","block":{"hashables":{"type":0}}}`,
+		`{"type":"Send","block":{}}`,
+		`{"type":"Send","block":[]}`,
+	}
+
+	for _, input := range inputs {
+		if _, err := DecodeBlockJSON([]byte(input)); err == nil {
+			t.Errorf("DecodeBlockJSON(%v) succeeded", input)
+		}
+	}
+
+	block, err := DecodeBlockJSON([]byte("null"))
+
+	if (err != nil) || (block != nil) {
+		t.Errorf("DecodeBlockJSON(null) = %v, %v", block, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/gob"
 	"encoding/json"
 	"io"
+	"math/big"
 	"time"
 )
 
@@ -30,7 +31,7 @@ type ChangeHashables struct {
 // MakeChangeHashables creates and initializes a ChangeHashables from the given arguments.
 func MakeChangeHashables(amt Amount, delegates []IBAN, prev BlockHash) ChangeHashables {
 	return ChangeHashables{
-		Balance:   amt,
+		Balance:   NormalizeAmount(amt),
 		Delegates: delegates,
 		Previous:  prev,
 		Timestamp: time.Now().UnixNano(),
@@ -46,8 +47,7 @@ func (ch *ChangeHashables) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (ch *ChangeHashables) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(ch)
+	return decodeBalanceJSON(r, ch, &ch.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -74,7 +74,7 @@ type DelegateHashables struct {
 // MakeDelegateHashables creates and initializes a DelegateHashables from the given arguments.
 func MakeDelegateHashables(amt Amount, prev BlockHash, share float64) DelegateHashables {
 	return DelegateHashables{
-		Balance:   amt,
+		Balance:   NormalizeAmount(amt),
 		Previous:  prev,
 		Share:     share,
 		Timestamp: time.Now().UnixNano(),
@@ -90,8 +90,7 @@ func (dh *DelegateHashables) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (dh *DelegateHashables) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(dh)
+	return decodeBalanceJSON(r, dh, &dh.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -106,19 +105,71 @@ func (dh *DelegateHashables) SerializeJSON(w io.Writer) error {
 	return encoder.Encode(dh)
 }
 
+// NameHashables contains elements of a NameBlock that can be hashed.
+type NameHashables struct {
+	Action      NameAction `json:"action"`
+	Balance     Amount     `json:"balance"`
+	Destination IBAN       `json:"destination"`
+	Name        string     `json:"name"`
+	Previous    BlockHash  `json:"previous"`
+	Timestamp   int64      `json:"timestamp"`
+	Type        BlockType  `json:"type"`
+}
+
+// MakeNameHashables creates and initializes a NameHashables from the given arguments.
+func MakeNameHashables(amt Amount, action NameAction, name string, dst IBAN, prev BlockHash) NameHashables {
+	return NameHashables{
+		Action:      action,
+		Balance:     NormalizeAmount(amt),
+		Destination: dst,
+		Name:        name,
+		Previous:    prev,
+		Timestamp:   time.Now().UnixNano(),
+		Type:        Name,
+	}
+}
+
+// Deserialize decodes byte data encoded by gob.
+func (nh *NameHashables) Deserialize(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	return decoder.Decode(nh)
+}
+
+// DeserializeJSON decodes JSON data.
+func (nh *NameHashables) DeserializeJSON(r io.Reader) error {
+	return decodeBalanceJSON(r, nh, &nh.Balance)
+}
+
+// Serialize encodes to byte data using gob.
+func (nh *NameHashables) Serialize(w io.Writer) error {
+	encoder := gob.NewEncoder(w)
+	return encoder.Encode(nh)
+}
+
+// SerializeJSON encodes to JSON data.
+func (nh *NameHashables) SerializeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(nh)
+}
+
 // OpenHashables contains elements of a OpenBlock that can be hashed.
 type OpenHashables struct {
-	Account   IBAN      `json:"account"`
-	Balance   Amount    `json:"balance"`
+	Account IBAN   `json:"account"`
+	Balance Amount `json:"balance"`
+	// Public key the account is derived from if controlled by a single key
+	Key *PublicKey `json:"key,omitempty"`
+	// Multi-signature policy controlling the account if any
+	Policy    *Policy   `json:"policy,omitempty"`
 	Timestamp int64     `json:"timestamp"`
 	Type      BlockType `json:"type"`
 }
 
 // MakeOpenHashables creates and initializes a OpenHashables from the given arguments.
-func MakeOpenHashables(amt Amount, iban IBAN) OpenHashables {
+func MakeOpenHashables(amt Amount, iban IBAN, key *PublicKey) OpenHashables {
 	return OpenHashables{
 		Account:   iban,
-		Balance:   amt,
+		Balance:   NormalizeAmount(amt),
+		Key:       key,
 		Timestamp: time.Now().UnixNano(),
 		Type:      Open,
 	}
@@ -132,8 +183,7 @@ func (oh *OpenHashables) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (oh *OpenHashables) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(oh)
+	return decodeBalanceJSON(r, oh, &oh.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -160,7 +210,7 @@ type ReceiveHashables struct {
 // MakeReceiveHashables creates and initializes a ReceiveHashables from the given arguments.
 func MakeReceiveHashables(amt Amount, prev, src BlockHash) ReceiveHashables {
 	return ReceiveHashables{
-		Balance:   amt,
+		Balance:   NormalizeAmount(amt),
 		Previous:  prev,
 		Source:    src,
 		Timestamp: time.Now().UnixNano(),
@@ -176,8 +226,7 @@ func (rh *ReceiveHashables) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (rh *ReceiveHashables) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(rh)
+	return decodeBalanceJSON(r, rh, &rh.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -192,6 +241,52 @@ func (rh *ReceiveHashables) SerializeJSON(w io.Writer) error {
 	return encoder.Encode(rh)
 }
 
+// RotateHashables contains elements of a RotateBlock that can be hashed.
+type RotateHashables struct {
+	// Signature of RotationHash by the previously authorized key
+	Authorization Signature `json:"authorization"`
+	Balance       Amount    `json:"balance"`
+	Key           PublicKey `json:"key"`
+	Previous      BlockHash `json:"previous"`
+	Timestamp     int64     `json:"timestamp"`
+	Type          BlockType `json:"type"`
+}
+
+// MakeRotateHashables creates and initializes a RotateHashables from the given arguments.
+func MakeRotateHashables(amt Amount, key PublicKey, authorization Signature, prev BlockHash) RotateHashables {
+	return RotateHashables{
+		Authorization: authorization,
+		Balance:       NormalizeAmount(amt),
+		Key:           key,
+		Previous:      prev,
+		Timestamp:     time.Now().UnixNano(),
+		Type:          Rotate,
+	}
+}
+
+// Deserialize decodes byte data encoded by gob.
+func (rh *RotateHashables) Deserialize(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	return decoder.Decode(rh)
+}
+
+// DeserializeJSON decodes JSON data.
+func (rh *RotateHashables) DeserializeJSON(r io.Reader) error {
+	return decodeBalanceJSON(r, rh, &rh.Balance)
+}
+
+// Serialize encodes to byte data using gob.
+func (rh *RotateHashables) Serialize(w io.Writer) error {
+	encoder := gob.NewEncoder(w)
+	return encoder.Encode(rh)
+}
+
+// SerializeJSON encodes to JSON data.
+func (rh *RotateHashables) SerializeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(rh)
+}
+
 // SendHashables contains elements of a SendBlock that can be hashed.
 type SendHashables struct {
 	Balance     Amount    `json:"balance"`
@@ -204,7 +299,7 @@ type SendHashables struct {
 // MakeSendHashables creates and initializes a SendHashables from the given arguments.
 func MakeSendHashables(amt Amount, dst IBAN, prev BlockHash) SendHashables {
 	return SendHashables{
-		Balance:     amt,
+		Balance:     NormalizeAmount(amt),
 		Destination: dst,
 		Previous:    prev,
 		Timestamp:   time.Now().UnixNano(),
@@ -220,8 +315,7 @@ func (sh *SendHashables) Deserialize(r io.Reader) error {
 
 // DeserializeJSON decodes JSON data.
 func (sh *SendHashables) DeserializeJSON(r io.Reader) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(sh)
+	return decodeBalanceJSON(r, sh, &sh.Balance)
 }
 
 // Serialize encodes to byte data using gob.
@@ -235,3 +329,17 @@ func (sh *SendHashables) SerializeJSON(w io.Writer) error {
 	encoder := json.NewEncoder(w)
 	return encoder.Encode(sh)
 }
+
+// decodeBalanceJSON decodes JSON data into v parsing the balance at AmountPrecision.
+// Parsing at the precision of the original and normalizing reproduces the amount that was hashed.
+func decodeBalanceJSON(r io.Reader, v interface{}, balance *Amount) error {
+	*balance = new(big.Float).SetPrec(AmountPrecision)
+	decoder := json.NewDecoder(r)
+
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+
+	*balance = NormalizeAmount(*balance)
+	return nil
+}
@@ -4,14 +4,44 @@ import (
 	"bytes"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
 	"strconv"
+	"strings"
 )
 
 // IBANSize is the fixed length of an International Bank Account Number
 const IBANSize = 34
 
+// CountryCode is the country code all IBANs in the system start with.
+const CountryCode = "TV"
+
+// Reasons an IBAN can fail validation
+var (
+	ErrIBANCharacter   = errors.New("Invalid character")
+	ErrIBANChecksum    = errors.New("Invalid checksum")
+	ErrIBANCountryCode = errors.New("Invalid country code")
+	ErrIBANLength      = errors.New("Invalid length")
+)
+
+// IBANError is returned when an IBAN fails validation.
+type IBANError struct {
+	Input string
+	Err   error
+}
+
+// Error returns the reason the input failed validation.
+func (e *IBANError) Error() string {
+	return fmt.Sprintf("Invalid IBAN %q: %v", e.Input, e.Err)
+}
+
+// Unwrap returns the reason the input failed validation.
+func (e *IBANError) Unwrap() error {
+	return e.Err
+}
+
 // IBAN represents an International Bank Account Number
 // which consists of up to 34 alphanumeric characters.
 // Country Code - 2 bytes
@@ -20,19 +50,56 @@ const IBANSize = 34
 type IBAN [IBANSize]byte
 
 // MakeIBAN creates and initializes an IBAN from the given bytes.
-// Takes a slice of bytes in the format of an IBAN.
+// Takes a slice of bytes in the format of an IBAN and fills in the checksum.
 func MakeIBAN(b []byte) IBAN {
 	var iban IBAN
 	copy(iban[:], b)
-	copy(b[IBANSize-4:], iban[:4])
-	copy(b[:IBANSize-4], iban[4:])
-	numeric := iban.ConvertToNumeric(b)
-	integer := iban.ConvertToInteger(numeric)
-	checksum := iban.CalculateChecksum(integer)
+	iban.SetChecksum([]byte("00"))
+	checksum := iban.CalculateChecksum(iban.Integer())
 	iban.SetChecksum(checksum)
 	return iban
 }
 
+// ParseIBAN parses and validates the given IBAN in either electronic or printable format.
+func ParseIBAN(s string) (IBAN, error) {
+	var iban IBAN
+	electronic := strings.ToUpper(strings.Replace(s, " ", "", -1))
+
+	if len(electronic) != IBANSize {
+		return iban, &IBANError{Input: s, Err: ErrIBANLength}
+	}
+
+	copy(iban[:], electronic)
+
+	if err := iban.Validate(); err != nil {
+		return IBAN{}, &IBANError{Input: s, Err: err}
+	}
+
+	return iban, nil
+}
+
+// IBANFromBBAN creates and initializes an IBAN with a checksum for the given BBAN.
+func IBANFromBBAN(bban BBAN) IBAN {
+	return MakeIBAN([]byte(CountryCode + "00" + bban.String()))
+}
+
+// Address returns the Address of an IBAN using the direct ICAP encoding.
+func (iban *IBAN) Address() (Address, error) {
+	if err := iban.Validate(); err != nil {
+		return Address{}, err
+	}
+
+	bban := iban.BBAN()
+	return bban.Address()
+}
+
+// BBAN returns the BBAN portion of the IBAN.
+func (iban *IBAN) BBAN() BBAN {
+	var bban BBAN
+	bban.SetBytes(iban[4:])
+	return bban
+}
+
 // CalculateChecksum calcuates the checksum of the IBAN.
 func (iban *IBAN) CalculateChecksum(i *big.Int) []byte {
 	mod := big.NewInt(97)
@@ -62,6 +129,15 @@ func (iban *IBAN) ConvertToNumeric(b []byte) []byte {
 	return b
 }
 
+// Integer returns the numeric representation of the IBAN with the first 4 bytes moved to the end.
+func (iban *IBAN) Integer() *big.Int {
+	rearranged := make([]byte, IBANSize)
+	copy(rearranged, iban[4:])
+	copy(rearranged[IBANSize-4:], iban[:4])
+	numeric := iban.ConvertToNumeric(rearranged)
+	return iban.ConvertToInteger(numeric)
+}
+
 // ConvertToInteger takes the numeric representation in bytes and converts it to a big.Int.
 func (iban *IBAN) ConvertToInteger(b []byte) *big.Int {
 	var buffer bytes.Buffer
@@ -83,6 +159,32 @@ func (iban *IBAN) SetChecksum(b []byte) {
 	}
 }
 
+// Validate returns the reason the IBAN is invalid or nil if it is valid.
+func (iban *IBAN) Validate() error {
+	if string(iban[:2]) != CountryCode {
+		return ErrIBANCountryCode
+	}
+
+	// Checksum must be numeric while the BBAN may be alphanumeric.
+	for i, c := range iban[2:] {
+		isDigit := (c >= '0') && (c <= '9')
+		isUpper := (c >= 'A') && (c <= 'Z')
+
+		if !isDigit && !(isUpper && (i >= 2)) {
+			return ErrIBANCharacter
+		}
+	}
+
+	remainder := new(big.Int)
+	remainder.Mod(iban.Integer(), big.NewInt(97))
+
+	if remainder.Int64() != 1 {
+		return ErrIBANChecksum
+	}
+
+	return nil
+}
+
 // Deserialize decodes byte data encoded by gob.
 func (iban *IBAN) Deserialize(r io.Reader) error {
 	decoder := gob.NewDecoder(r)
@@ -107,7 +209,33 @@ func (iban *IBAN) SerializeJSON(w io.Writer) error {
 	return encoder.Encode(iban)
 }
 
+// Electronic returns the IBAN without any spaces.
+func (iban *IBAN) Electronic() string {
+	return string(iban[:])
+}
+
+// Printable returns the IBAN in groups of 4 characters separated by spaces.
+func (iban *IBAN) Printable() string {
+	var buffer bytes.Buffer
+
+	for i := 0; i < IBANSize; i += 4 {
+		if i > 0 {
+			buffer.WriteByte(' ')
+		}
+
+		end := i + 4
+
+		if end > IBANSize {
+			end = IBANSize
+		}
+
+		buffer.Write(iban[i:end])
+	}
+
+	return buffer.String()
+}
+
 // String returns the string representation of the IBAN.
 func (iban *IBAN) String() string {
-	return string(iban[:])
+	return iban.Electronic()
 }
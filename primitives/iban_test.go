@@ -0,0 +1,123 @@
+package primitives
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func mustAddress(t *testing.T, s string) Address {
+	t.Helper()
+	b, err := hex.DecodeString(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return MakeAddress(b)
+}
+
+func TestIBANRoundTrip(t *testing.T) {
+	addresses := []string{
+		"0000000000000000000000000000000000000000",
+		"00c5496aee77c1ba1f0854206a26dda82a81d6d8",
+		"088f924eeceeda7fe92e1f5b0fffffffffffffff",
+	}
+
+	for _, s := range addresses {
+		address := mustAddress(t, s)
+		iban, err := IBANFromAddress(address)
+
+		if err != nil {
+			t.Fatalf("IBANFromAddress(%v): %v", s, err)
+		}
+
+		for _, input := range []string{iban.Electronic(), iban.Printable(), strings.ToLower(iban.Printable())} {
+			parsed, err := ParseIBAN(input)
+
+			if err != nil {
+				t.Fatalf("ParseIBAN(%q): %v", input, err)
+			}
+
+			if parsed != iban {
+				t.Errorf("ParseIBAN(%q) = %v, want %v", input, parsed.String(), iban.String())
+			}
+		}
+
+		decoded, err := iban.Address()
+
+		if err != nil {
+			t.Fatalf("Address of %v: %v", iban.String(), err)
+		}
+
+		if decoded != address {
+			t.Errorf("Address of %v = %x, want %v", iban.String(), decoded, s)
+		}
+	}
+}
+
+func TestIBANPrintable(t *testing.T) {
+	iban, err := ParseIBAN("TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZS")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "TV58 38O0 73KY GTWW ZN0F 2WZ0 R8PX 5ZPP ZS"
+
+	if got := iban.Printable(); got != want {
+		t.Errorf("Printable() = %q, want %q", got, want)
+	}
+}
+
+func TestParseIBANErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{"", ErrIBANLength},
+		{"TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZ", ErrIBANLength},
+		{"TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZSS", ErrIBANLength},
+		{"XE7338O073KYGTWWZN0F2WZ0R8PX5ZPPZS", ErrIBANCountryCode},
+		{"TV5A38O073KYGTWWZN0F2WZ0R8PX5ZPPZS", ErrIBANCharacter},
+		{"TV5838O073KYGTWWZN0F2WZ0R8PX5ZPP-S", ErrIBANCharacter},
+		{"TV5938O073KYGTWWZN0F2WZ0R8PX5ZPPZS", ErrIBANChecksum},
+		{"TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZT", ErrIBANChecksum},
+	}
+
+	for _, test := range tests {
+		_, err := ParseIBAN(test.input)
+
+		if !errors.Is(err, test.err) {
+			t.Errorf("ParseIBAN(%q) = %v, want %v", test.input, err, test.err)
+			continue
+		}
+
+		var ibanErr *IBANError
+
+		if !errors.As(err, &ibanErr) || (ibanErr.Input != test.input) {
+			t.Errorf("ParseIBAN(%q) = %#v, want IBANError with input", test.input, err)
+		}
+	}
+}
+
+func TestIBANValidate(t *testing.T) {
+	var bban BBAN
+	bban.SetBytes([]byte("38O073KYGTWWZN0F2WZ0R8PX5ZPPZS"))
+	iban := IBANFromBBAN(bban)
+
+	if err := iban.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	if got := iban.String(); got != "TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZS" {
+		t.Errorf("IBANFromBBAN = %v", got)
+	}
+
+	iban[10] = 'a'
+
+	if err := iban.Validate(); err != ErrIBANCharacter {
+		t.Errorf("Validate() with lowercase = %v, want %v", err, ErrIBANCharacter)
+	}
+}
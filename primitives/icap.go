@@ -0,0 +1,269 @@
+package primitives
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/kookehs/watchmen/encoding/base36"
+)
+
+// ICAPKind is used to represent the different encodings of the Inter-exchange Client Address Protocol.
+type ICAPKind uint8
+
+// Various ICAP encodings
+const (
+	// ICAPDirect encodes an Address below 2^155 in 30 base36 characters.
+	ICAPDirect ICAPKind = iota
+	// ICAPBasic encodes any Address in 31 base36 characters and is not IBAN compatible.
+	ICAPBasic
+	// ICAPIndirect encodes an asset, institution and client identifier.
+	ICAPIndirect
+)
+
+// Lengths of the BBAN portion of each ICAP encoding
+const (
+	ICAPBasicSize       = 31
+	ICAPDirectSize      = BBANSize
+	ICAPIndirectSize    = ICAPAssetSize + ICAPInstitutionSize + ICAPClientSize
+	ICAPAssetSize       = 3
+	ICAPInstitutionSize = 4
+	ICAPClientSize      = 9
+)
+
+// Reasons an ICAP can fail to be encoded or decoded
+var (
+	ErrICAPAddressTooLarge = errors.New("Address is too large for direct encoding")
+	ErrICAPKind            = errors.New("Unknown ICAP encoding")
+)
+
+// icapDirectLimit is the smallest integer that cannot be encoded in ICAPDirectSize characters.
+var icapDirectLimit = new(big.Int).Exp(big.NewInt(36), big.NewInt(ICAPDirectSize), nil)
+
+// ICAPCompatible returns whether the Address can be encoded using the direct ICAP encoding.
+func (a *Address) ICAPCompatible() bool {
+	return new(big.Int).SetBytes(a[:]).Cmp(icapDirectLimit) == -1
+}
+
+// ICAP contains the decoded fields of an Inter-exchange Client Address Protocol code.
+// Address is set for direct and basic encodings while the identifiers are set for indirect encodings.
+type ICAP struct {
+	Address     Address
+	Asset       string
+	Client      string
+	Institution string
+	Kind        ICAPKind
+}
+
+// NewDirectICAP creates and initializes a direct ICAP for the given Address.
+func NewDirectICAP(a Address) *ICAP {
+	return &ICAP{
+		Address: a,
+		Kind:    ICAPDirect,
+	}
+}
+
+// NewBasicICAP creates and initializes a basic ICAP for the given Address.
+func NewBasicICAP(a Address) *ICAP {
+	return &ICAP{
+		Address: a,
+		Kind:    ICAPBasic,
+	}
+}
+
+// NewIndirectICAP creates and initializes an indirect ICAP from the given identifiers.
+func NewIndirectICAP(asset, institution, client string) *ICAP {
+	return &ICAP{
+		Asset:       strings.ToUpper(asset),
+		Client:      strings.ToUpper(client),
+		Institution: strings.ToUpper(institution),
+		Kind:        ICAPIndirect,
+	}
+}
+
+// ParseICAP decodes the given ICAP code determining the encoding from its length.
+func ParseICAP(s string) (*ICAP, error) {
+	code := strings.ToUpper(strings.Replace(s, " ", "", -1))
+
+	if len(code) < 4 {
+		return nil, &IBANError{Input: s, Err: ErrIBANLength}
+	}
+
+	if code[:2] != CountryCode {
+		return nil, &IBANError{Input: s, Err: ErrIBANCountryCode}
+	}
+
+	body := code[4:]
+
+	if !isAlphanumeric(body) || !isNumeric(code[2:4]) {
+		return nil, &IBANError{Input: s, Err: ErrIBANCharacter}
+	}
+
+	if ICAPChecksum(body) != code[2:4] {
+		return nil, &IBANError{Input: s, Err: ErrIBANChecksum}
+	}
+
+	switch len(body) {
+	case ICAPDirectSize, ICAPBasicSize:
+		address, err := decodeICAPAddress(body)
+
+		if err != nil {
+			return nil, &IBANError{Input: s, Err: err}
+		}
+
+		icap := NewDirectICAP(address)
+
+		if len(body) == ICAPBasicSize {
+			icap.Kind = ICAPBasic
+		}
+
+		return icap, nil
+	case ICAPIndirectSize:
+		asset := body[:ICAPAssetSize]
+		institution := body[ICAPAssetSize : ICAPAssetSize+ICAPInstitutionSize]
+		client := body[ICAPAssetSize+ICAPInstitutionSize:]
+		return NewIndirectICAP(asset, institution, client), nil
+	default:
+		return nil, &IBANError{Input: s, Err: ErrIBANLength}
+	}
+}
+
+// BBAN returns the BBAN portion of the ICAP without country code and checksum.
+func (icap *ICAP) BBAN() (string, error) {
+	switch icap.Kind {
+	case ICAPDirect:
+		encoded, err := base36.EncodeFixed(icap.Address[:], ICAPDirectSize)
+
+		if err != nil {
+			return "", ErrICAPAddressTooLarge
+		}
+
+		return string(encoded), nil
+	case ICAPBasic:
+		encoded, err := base36.EncodeFixed(icap.Address[:], ICAPBasicSize)
+
+		if err != nil {
+			return "", err
+		}
+
+		return string(encoded), nil
+	case ICAPIndirect:
+		if (len(icap.Asset) != ICAPAssetSize) || (len(icap.Institution) != ICAPInstitutionSize) || (len(icap.Client) != ICAPClientSize) {
+			return "", ErrIBANLength
+		}
+
+		bban := icap.Asset + icap.Institution + icap.Client
+
+		if !isAlphanumeric(bban) {
+			return "", ErrIBANCharacter
+		}
+
+		return bban, nil
+	default:
+		return "", ErrICAPKind
+	}
+}
+
+// IBAN returns the ICAP as an IBAN. Only direct encodings are IBAN compatible.
+func (icap *ICAP) IBAN() (IBAN, error) {
+	if icap.Kind != ICAPDirect {
+		return IBAN{}, fmt.Errorf("ICAP encoding %v is not IBAN compatible", icap.Kind)
+	}
+
+	bban, err := icap.BBAN()
+
+	if err != nil {
+		return IBAN{}, err
+	}
+
+	var b BBAN
+	b.SetBytes([]byte(bban))
+	return IBANFromBBAN(b), nil
+}
+
+// String returns the encoded ICAP or an empty string if it cannot be encoded.
+func (icap *ICAP) String() string {
+	bban, err := icap.BBAN()
+
+	if err != nil {
+		return ""
+	}
+
+	return CountryCode + ICAPChecksum(bban) + bban
+}
+
+// ICAPChecksum returns the 2 digit mod-97 checksum for the given BBAN of any length.
+func ICAPChecksum(bban string) string {
+	var buffer strings.Builder
+
+	for _, c := range bban + CountryCode + "00" {
+		if (c >= 'A') && (c <= 'Z') {
+			buffer.WriteString(fmt.Sprint(c - 'A' + 10))
+		} else {
+			buffer.WriteRune(c)
+		}
+	}
+
+	integer := new(big.Int)
+	integer.SetString(buffer.String(), 10)
+	integer.Mod(integer, big.NewInt(97))
+	return fmt.Sprintf("%02d", 98-integer.Int64())
+}
+
+// BBANFromAddress returns the direct ICAP encoding of the given Address as a BBAN.
+func BBANFromAddress(a Address) (BBAN, error) {
+	var bban BBAN
+	encoded, err := NewDirectICAP(a).BBAN()
+
+	if err != nil {
+		return bban, err
+	}
+
+	bban.SetBytes([]byte(encoded))
+	return bban, nil
+}
+
+// IBANFromAddress returns the direct ICAP encoding of the given Address as an IBAN.
+func IBANFromAddress(a Address) (IBAN, error) {
+	return NewDirectICAP(a).IBAN()
+}
+
+// decodeICAPAddress decodes the given base36 characters into an Address.
+func decodeICAPAddress(s string) (Address, error) {
+	var address Address
+	decoded, err := base36.DecodeFixed([]byte(s), AddressSize)
+
+	if err != nil {
+		if _, ok := err.(*base36.SizeError); ok {
+			return address, ErrICAPAddressTooLarge
+		}
+
+		return address, ErrIBANCharacter
+	}
+
+	address.SetBytes(decoded)
+	return address, nil
+}
+
+// isAlphanumeric returns whether the given string only contains digits and uppercase letters.
+func isAlphanumeric(s string) bool {
+	for _, c := range s {
+		if !((c >= '0') && (c <= '9')) && !((c >= 'A') && (c <= 'Z')) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isNumeric returns whether the given string only contains digits.
+func isNumeric(s string) bool {
+	for _, c := range s {
+		if (c < '0') || (c > '9') {
+			return false
+		}
+	}
+
+	return true
+}
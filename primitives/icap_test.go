@@ -0,0 +1,123 @@
+package primitives
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestICAPAddressVectors(t *testing.T) {
+	tests := []struct {
+		address string
+		kind    ICAPKind
+		code    string
+	}{
+		{"0000000000000000000000000000000000000000", ICAPDirect, "TV35000000000000000000000000000000"},
+		{"00c5496aee77c1ba1f0854206a26dda82a81d6d8", ICAPDirect, "TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZS"},
+		// Largest Address that fits the direct encoding: 36^30 - 1
+		{"088f924eeceeda7fe92e1f5b0fffffffffffffff", ICAPDirect, "TV28ZZZZZZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{"00c5496aee77c1ba1f0854206a26dda82a81d6d8", ICAPBasic, "TV58038O073KYGTWWZN0F2WZ0R8PX5ZPPZS"},
+		// Smallest Address that requires the basic encoding: 36^30
+		{"088f924eeceeda7fe92e1f5b1000000000000000", ICAPBasic, "TV681000000000000000000000000000000"},
+		{"ffffffffffffffffffffffffffffffffffffffff", ICAPBasic, "TV80TWJ4YIDKW7A8PN4G709KZMFOAOL3X8F"},
+	}
+
+	for _, test := range tests {
+		address := mustAddress(t, test.address)
+		icap := &ICAP{Address: address, Kind: test.kind}
+
+		if got := icap.String(); got != test.code {
+			t.Errorf("ICAP %v of %v = %q, want %q", test.kind, test.address, got, test.code)
+		}
+
+		parsed, err := ParseICAP(test.code)
+
+		if err != nil {
+			t.Fatalf("ParseICAP(%q): %v", test.code, err)
+		}
+
+		if (parsed.Kind != test.kind) || (parsed.Address != address) {
+			t.Errorf("ParseICAP(%q) = %v %x, want %v %v", test.code, parsed.Kind, parsed.Address, test.kind, test.address)
+		}
+
+		if (test.kind == ICAPDirect) && !address.ICAPCompatible() {
+			t.Errorf("ICAPCompatible() of %v = false", test.address)
+		}
+	}
+}
+
+func TestICAPDirectLimit(t *testing.T) {
+	limit := mustAddress(t, "088f924eeceeda7fe92e1f5b1000000000000000")
+
+	if limit.ICAPCompatible() {
+		t.Error("ICAPCompatible() of 36^30 = true")
+	}
+
+	if _, err := NewDirectICAP(limit).BBAN(); err != ErrICAPAddressTooLarge {
+		t.Errorf("BBAN() of 36^30 = %v, want %v", err, ErrICAPAddressTooLarge)
+	}
+
+	if _, err := IBANFromAddress(limit); err != ErrICAPAddressTooLarge {
+		t.Errorf("IBANFromAddress(36^30) = %v, want %v", err, ErrICAPAddressTooLarge)
+	}
+
+	if got := NewDirectICAP(limit).String(); got != "" {
+		t.Errorf("String() of 36^30 = %q, want empty", got)
+	}
+
+	below := mustAddress(t, "088f924eeceeda7fe92e1f5b0fffffffffffffff")
+	iban, err := IBANFromAddress(below)
+
+	if err != nil {
+		t.Fatalf("IBANFromAddress(36^30 - 1): %v", err)
+	}
+
+	if got := iban.String(); got != "TV28ZZZZZZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("IBANFromAddress(36^30 - 1) = %v", got)
+	}
+}
+
+func TestICAPIndirect(t *testing.T) {
+	icap := NewIndirectICAP("eth", "xreg", "kittycats")
+
+	if got := icap.String(); got != "TV39ETHXREGKITTYCATS" {
+		t.Errorf("String() = %q, want %q", got, "TV39ETHXREGKITTYCATS")
+	}
+
+	parsed, err := ParseICAP("tv39 ethx regk itty cats")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if (parsed.Kind != ICAPIndirect) || (parsed.Asset != "ETH") || (parsed.Institution != "XREG") || (parsed.Client != "KITTYCATS") {
+		t.Errorf("ParseICAP = %+v", parsed)
+	}
+
+	if _, err := parsed.IBAN(); err == nil {
+		t.Error("IBAN() of indirect ICAP succeeded")
+	}
+
+	if got := NewIndirectICAP("ETH", "XREG", "KITTY").String(); got != "" {
+		t.Errorf("String() with short client = %q, want empty", got)
+	}
+}
+
+func TestParseICAPErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{"TV3", ErrIBANLength},
+		{"TV87ETHXREG", ErrIBANLength},
+		{"XE7338O073KYGTWWZN0F2WZ0R8PX5ZPPZS", ErrIBANCountryCode},
+		{"TV5838O073KYGTWWZN0F2WZ0R8PX5ZPP-S", ErrIBANCharacter},
+		{"TV5938O073KYGTWWZN0F2WZ0R8PX5ZPPZS", ErrIBANChecksum},
+		{"TV39ZZZZZZZZZZZZZZZZZZZZZZZZZZZZZZZ", ErrICAPAddressTooLarge},
+	}
+
+	for _, test := range tests {
+		if _, err := ParseICAP(test.input); !errors.Is(err, test.err) {
+			t.Errorf("ParseICAP(%q) = %v, want %v", test.input, err, test.err)
+		}
+	}
+}
@@ -3,9 +3,10 @@ package primitives
 import (
 	"bytes"
 	"crypto/ecdsa"
-	"crypto/elliptic"
+	"crypto/ed25519"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
 	"io"
 
 	"github.com/google/uuid"
@@ -13,52 +14,184 @@ import (
 )
 
 // Key contains all the unique values to generate an address and private key.
+// Only the private key matching Scheme is set.
 type Key struct {
-	ID         uuid.UUID         `json:"id"`
-	Address    Address           `json:"address"`
-	PrivateKey *ecdsa.PrivateKey `json:"privatekey"`
+	ID         uuid.UUID          `json:"id"`
+	Address    Address            `json:"address"`
+	Ed25519    ed25519.PrivateKey `json:"ed25519,omitempty"`
+	PrivateKey *ecdsa.PrivateKey  `json:"privatekey,omitempty"`
+	Scheme     crypto.Scheme      `json:"scheme"`
 }
 
 // NewKeyFromECDSA creates and initializes a Key generated from the given ECDSA private key.
 func NewKeyFromECDSA(priv *ecdsa.PrivateKey) (*Key, error) {
+	return NewKeyFromSigner(crypto.NewP256Signer(priv))
+}
+
+// NewKeyFromEd25519 creates and initializes a Key generated from the given Ed25519 private key.
+func NewKeyFromEd25519(priv ed25519.PrivateKey) (*Key, error) {
+	return NewKeyFromSigner(crypto.NewEd25519Signer(priv))
+}
+
+// NewKeyFromSigner creates and initializes a Key generated from the given private key.
+func NewKeyFromSigner(priv crypto.Signer) (*Key, error) {
 	id, err := uuid.NewRandom()
 
 	if err != nil {
 		return nil, err
 	}
 
-	hash := crypto.ECDSAPublicKeyToSHA256(priv.PublicKey)
-	address := MakeAddress(hash[:])
+	key := &Key{
+		ID:      id,
+		Address: AddressFromPublicKey(priv.Verifier()),
+		Scheme:  priv.Scheme(),
+	}
+
+	switch signer := priv.(type) {
+	case *crypto.P256Signer:
+		key.PrivateKey = signer.PrivateKey
+	case *crypto.Ed25519Signer:
+		key.Ed25519 = signer.PrivateKey
+	default:
+		return nil, errors.New("Unsupported private key")
+	}
 
-	return &Key{
-		ID:         id,
-		Address:    address,
-		PrivateKey: priv,
-	}, nil
+	return key, nil
 }
 
-// NewKeyForICAP creates and initializes a Key for the Inter-exchange Client Address Protocol.
+// NewKeyForICAP creates and initializes a P-256 Key for the Inter-exchange Client Address Protocol.
 func NewKeyForICAP(r io.Reader) (*Key, error) {
+	return NewSchemeKeyForICAP(crypto.P256, r)
+}
+
+// NewSchemeKeyForICAP creates and initializes a Key of the given scheme for the Inter-exchange Client Address Protocol.
+// Keys are generated until the Address can be represented with the direct ICAP encoding.
+func NewSchemeKeyForICAP(scheme crypto.Scheme, r io.Reader) (*Key, error) {
 	noise := make([]byte, 64)
 
-	if _, err := r.Read(noise); err != nil {
-		return nil, err
+	for {
+		if _, err := io.ReadFull(r, noise); err != nil {
+			return nil, err
+		}
+
+		reader := bytes.NewReader(noise)
+		priv, err := crypto.GenerateSigner(scheme, reader)
+
+		if err != nil {
+			return nil, err
+		}
+
+		address := AddressFromPublicKey(priv.Verifier())
+
+		if !address.ICAPCompatible() {
+			continue
+		}
+
+		return NewKeyFromSigner(priv)
 	}
+}
 
-	reader := bytes.NewReader(noise)
-	privateKeyECDSA, err := ecdsa.GenerateKey(elliptic.P256(), reader)
+// AddressFromPublicKey returns the Address derived from the given public key.
+func AddressFromPublicKey(pub crypto.Verifier) Address {
+	hash := crypto.PublicKeyToSHA256(pub)
+	return MakeAddress(hash[:])
+}
+
+// VerifyIBAN returns whether the given IBAN is the direct ICAP encoding of the given public key's Address.
+func VerifyIBAN(iban IBAN, pub crypto.Verifier) bool {
+	if pub == nil {
+		return false
+	}
+
+	address, err := iban.Address()
 
 	if err != nil {
-		return nil, err
+		return false
+	}
+
+	return address == AddressFromPublicKey(pub)
+}
+
+// keyJSON is the JSON representation of a Key.
+// The private key is stored as the bytes read by crypto.ParseSigner.
+type keyJSON struct {
+	Address Address       `json:"address"`
+	ID      uuid.UUID     `json:"id"`
+	Private []byte        `json:"private"`
+	Scheme  crypto.Scheme `json:"scheme"`
+}
+
+// MarshalJSON encodes the Key including its private key.
+func (k *Key) MarshalJSON() ([]byte, error) {
+	var private []byte
+
+	switch k.Scheme {
+	case crypto.P256:
+		if k.PrivateKey == nil {
+			return nil, errors.New("Key does not contain a P-256 private key")
+		}
+
+		private = k.PrivateKey.D.FillBytes(make([]byte, (k.PrivateKey.Curve.Params().BitSize+7)/8))
+	case crypto.Ed25519:
+		if len(k.Ed25519) != ed25519.PrivateKeySize {
+			return nil, errors.New("Key does not contain an Ed25519 private key")
+		}
+
+		private = k.Ed25519.Seed()
+	default:
+		return nil, errors.New("Unknown signature scheme")
+	}
+
+	return json.Marshal(keyJSON{
+		Address: k.Address,
+		ID:      k.ID,
+		Private: private,
+		Scheme:  k.Scheme,
+	})
+}
+
+// UnmarshalJSON decodes the Key and verifies that its private key derives its Address.
+func (k *Key) UnmarshalJSON(data []byte) error {
+	var decoded keyJSON
+
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
 	}
 
-	key, err := NewKeyFromECDSA(privateKeyECDSA)
+	priv, err := crypto.ParseSigner(decoded.Scheme, decoded.Private)
 
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	return key, nil
+	if AddressFromPublicKey(priv.Verifier()) != decoded.Address {
+		return errors.New("Private key does not match the address of the key")
+	}
+
+	key, err := NewKeyFromSigner(priv)
+
+	if err != nil {
+		return err
+	}
+
+	key.ID = decoded.ID
+	*k = *key
+	return nil
+}
+
+// Signer returns the private key of the Key for its scheme.
+func (k *Key) Signer() crypto.Signer {
+	switch k.Scheme {
+	case crypto.Ed25519:
+		return crypto.NewEd25519Signer(k.Ed25519)
+	default:
+		return crypto.NewP256Signer(k.PrivateKey)
+	}
+}
+
+// Verifier returns the public key of the Key for its scheme.
+func (k *Key) Verifier() crypto.Verifier {
+	return k.Signer().Verifier()
 }
 
 // Deserialize decodes byte data encoded by gob.
@@ -0,0 +1,170 @@
+package primitives
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/kookehs/watchmen/crypto"
+)
+
+// MaxPolicyKeys is the maximum number of keys in a Policy.
+const MaxPolicyKeys = 16
+
+// PublicKey is the serializable form of a crypto.Verifier.
+type PublicKey struct {
+	Bytes  []byte        `json:"bytes"`
+	Scheme crypto.Scheme `json:"scheme"`
+}
+
+// MakePublicKey creates and initializes a PublicKey from the given public key.
+func MakePublicKey(pub crypto.Verifier) PublicKey {
+	return PublicKey{
+		Bytes:  pub.Bytes(),
+		Scheme: pub.Scheme(),
+	}
+}
+
+// Verifier returns the public key as a crypto.Verifier.
+func (pk *PublicKey) Verifier() (crypto.Verifier, error) {
+	return crypto.ParseVerifier(pk.Scheme, pk.Bytes)
+}
+
+// Policy is an m-of-n multi-signature policy declared in the OpenBlock of an account.
+// Blocks of the account must carry valid signatures from at least Threshold of Keys.
+type Policy struct {
+	Keys []PublicKey `json:"keys"`
+	// Nonce is chosen so that the Address is ICAP compatible
+	Nonce     uint32 `json:"nonce"`
+	Threshold int    `json:"threshold"`
+}
+
+// NewPolicy creates and initializes a Policy requiring threshold signatures of the given keys.
+// Keys are sorted so that the same set of keys always results in the same Address.
+func NewPolicy(threshold int, keys []crypto.Verifier) (*Policy, error) {
+	policy := &Policy{
+		Keys:      make([]PublicKey, 0, len(keys)),
+		Nonce:     0,
+		Threshold: threshold,
+	}
+
+	for _, key := range keys {
+		if key == nil {
+			return nil, errors.New("Policy keys must not be nil")
+		}
+
+		policy.Keys = append(policy.Keys, MakePublicKey(key))
+	}
+
+	sort.Slice(policy.Keys, func(i, j int) bool {
+		if policy.Keys[i].Scheme != policy.Keys[j].Scheme {
+			return policy.Keys[i].Scheme < policy.Keys[j].Scheme
+		}
+
+		return bytes.Compare(policy.Keys[i].Bytes, policy.Keys[j].Bytes) == -1
+	})
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	for address := policy.Address(); !address.ICAPCompatible(); address = policy.Address() {
+		policy.Nonce++
+	}
+
+	return policy, nil
+}
+
+// Address returns the Address derived from the keys, nonce and threshold of the Policy.
+func (p *Policy) Address() Address {
+	var buffer bytes.Buffer
+	buffer.WriteString("multisig")
+	binary.Write(&buffer, binary.BigEndian, uint32(p.Threshold))
+	binary.Write(&buffer, binary.BigEndian, p.Nonce)
+
+	for _, key := range p.Keys {
+		buffer.WriteByte(byte(key.Scheme))
+		binary.Write(&buffer, binary.BigEndian, uint32(len(key.Bytes)))
+		buffer.Write(key.Bytes)
+	}
+
+	hash := sha256.Sum256(buffer.Bytes())
+	return MakeAddress(hash[:])
+}
+
+// Validate returns whether the Policy can be satisfied and all of its keys are valid.
+func (p *Policy) Validate() error {
+	if (len(p.Keys) == 0) || (len(p.Keys) > MaxPolicyKeys) {
+		return fmt.Errorf("Policy must have between 1 and %v keys", MaxPolicyKeys)
+	}
+
+	if (p.Threshold < 1) || (p.Threshold > len(p.Keys)) {
+		return fmt.Errorf("Policy threshold must be between 1 and %v", len(p.Keys))
+	}
+
+	for i := range p.Keys {
+		if _, err := p.Keys[i].Verifier(); err != nil {
+			return err
+		}
+
+		for j := 0; j < i; j++ {
+			if (p.Keys[i].Scheme == p.Keys[j].Scheme) && bytes.Equal(p.Keys[i].Bytes, p.Keys[j].Bytes) {
+				return errors.New("Policy keys must be unique")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Verify returns whether the given signatures of the hash were made by at least Threshold distinct keys.
+func (p *Policy) Verify(hash []byte, signatures []Signature) bool {
+	signed := 0
+
+	for i := range p.Keys {
+		pub, err := p.Keys[i].Verifier()
+
+		if err != nil {
+			return false
+		}
+
+		for j := range signatures {
+			if signatures[j].Verify(hash, pub) {
+				signed++
+				break
+			}
+		}
+	}
+
+	return signed >= p.Threshold
+}
+
+// Deserialize decodes byte data encoded by gob.
+func (p *Policy) Deserialize(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	return decoder.Decode(p)
+}
+
+// DeserializeJSON decodes JSON data.
+func (p *Policy) DeserializeJSON(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	return decoder.Decode(p)
+}
+
+// Serialize encodes to byte data using gob.
+func (p *Policy) Serialize(w io.Writer) error {
+	encoder := gob.NewEncoder(w)
+	return encoder.Encode(p)
+}
+
+// SerializeJSON encodes to JSON data.
+func (p *Policy) SerializeJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	return encoder.Encode(p)
+}
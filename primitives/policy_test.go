@@ -0,0 +1,135 @@
+package primitives
+
+import (
+	"testing"
+
+	"github.com/kookehs/watchmen/crypto"
+)
+
+func TestNewPolicy(t *testing.T) {
+	signers := []crypto.Signer{mustSigner(t, crypto.P256), mustSigner(t, crypto.Ed25519), mustSigner(t, crypto.P256)}
+	keys := []crypto.Verifier{signers[0].Verifier(), signers[1].Verifier(), signers[2].Verifier()}
+	policy, err := NewPolicy(2, keys)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if address := policy.Address(); !address.ICAPCompatible() {
+		t.Errorf("Address %v of policy is not ICAP compatible", address)
+	}
+
+	// The same keys in any order derive the same Address.
+	reordered, err := NewPolicy(2, []crypto.Verifier{keys[2], keys[0], keys[1]})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reordered.Address() != policy.Address() {
+		t.Errorf("Address of reordered policy = %v, want %v", reordered.Address(), policy.Address())
+	}
+
+	if other, err := NewPolicy(3, keys); (err != nil) || (other.Address() == policy.Address()) {
+		t.Errorf("Policy with another threshold = %v, %v, want another address", other, err)
+	}
+
+	tests := []struct {
+		threshold int
+		keys      []crypto.Verifier
+	}{
+		{1, nil},
+		{0, keys},
+		{-1, keys},
+		{4, keys},
+		{2, []crypto.Verifier{keys[0], keys[0]}},
+		{1, []crypto.Verifier{keys[0], nil}},
+	}
+
+	for _, test := range tests {
+		if _, err := NewPolicy(test.threshold, test.keys); err == nil {
+			t.Errorf("NewPolicy(%v) of %v keys succeeded", test.threshold, len(test.keys))
+		}
+	}
+
+	many := make([]crypto.Verifier, MaxPolicyKeys+1)
+
+	for i := range many {
+		many[i] = mustSigner(t, crypto.Ed25519).Verifier()
+	}
+
+	if _, err := NewPolicy(1, many); err == nil {
+		t.Errorf("NewPolicy of %v keys succeeded", len(many))
+	}
+}
+
+func TestPolicyVerify(t *testing.T) {
+	members := []crypto.Signer{mustSigner(t, crypto.P256), mustSigner(t, crypto.Ed25519), mustSigner(t, crypto.P256)}
+	outsider := mustSigner(t, crypto.Ed25519)
+	policy, err := NewPolicy(2, []crypto.Verifier{members[0].Verifier(), members[1].Verifier(), members[2].Verifier()})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		signers []crypto.Signer
+		valid   bool
+	}{
+		{"no signers", nil, false},
+		{"threshold not met", members[:1], false},
+		{"duplicate signer", []crypto.Signer{members[0], members[0]}, false},
+		{"non-member signer", []crypto.Signer{members[0], outsider}, false},
+		{"threshold met", []crypto.Signer{members[2], members[1]}, true},
+		{"every member", append([]crypto.Signer{outsider}, members...), true},
+	}
+
+	destination := mustIBAN(t, "TV28ZZZZZZZZZZZZZZZZZZZZZZZZZZZZZZ")
+
+	for _, test := range tests {
+		block := NewSendBlock(NewAmount(1), destination, BlockHash{1})
+
+		for _, signer := range test.signers {
+			if err := block.Cosign(signer); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		verified, err := block.VerifyPolicy(policy)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if verified != test.valid {
+			t.Errorf("VerifyPolicy with %v = %v, want %v", test.name, verified, test.valid)
+		}
+
+		hash, err := block.Hash()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if policy.Verify(hash[:], block.Cosignatures) != test.valid {
+			t.Errorf("Verify with %v = %v, want %v", test.name, !test.valid, test.valid)
+		}
+	}
+
+	// Cosignatures of another block do not count.
+	block := NewSendBlock(NewAmount(1), destination, BlockHash{1})
+	other := NewSendBlock(NewAmount(2), destination, BlockHash{1})
+
+	for _, signer := range members {
+		if err := other.Cosign(signer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	block.Cosignatures = other.Cosignatures
+
+	if verified, err := block.VerifyPolicy(policy); (err != nil) || verified {
+		t.Errorf("VerifyPolicy with cosignatures of another block = %v, %v", verified, err)
+	}
+}
@@ -0,0 +1,1041 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: primitives/primitives.proto
+
+package primitivespb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Signature struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Compact R || S encoding
+	Rs            []byte `protobuf:"bytes,1,opt,name=rs,proto3" json:"rs,omitempty"`
+	Scheme        uint32 `protobuf:"varint,2,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Signature) Reset() {
+	*x = Signature{}
+	mi := &file_primitives_primitives_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Signature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signature) ProtoMessage() {}
+
+func (x *Signature) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Signature.ProtoReflect.Descriptor instead.
+func (*Signature) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Signature) GetRs() []byte {
+	if x != nil {
+		return x.Rs
+	}
+	return nil
+}
+
+func (x *Signature) GetScheme() uint32 {
+	if x != nil {
+		return x.Scheme
+	}
+	return 0
+}
+
+type PublicKey struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Bytes         []byte                 `protobuf:"bytes,1,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	Scheme        uint32                 `protobuf:"varint,2,opt,name=scheme,proto3" json:"scheme,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublicKey) Reset() {
+	*x = PublicKey{}
+	mi := &file_primitives_primitives_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublicKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublicKey) ProtoMessage() {}
+
+func (x *PublicKey) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublicKey.ProtoReflect.Descriptor instead.
+func (*PublicKey) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublicKey) GetBytes() []byte {
+	if x != nil {
+		return x.Bytes
+	}
+	return nil
+}
+
+func (x *PublicKey) GetScheme() uint32 {
+	if x != nil {
+		return x.Scheme
+	}
+	return 0
+}
+
+type Policy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []*PublicKey           `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Nonce         uint32                 `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	Threshold     int64                  `protobuf:"varint,3,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Policy) Reset() {
+	*x = Policy{}
+	mi := &file_primitives_primitives_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Policy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Policy) ProtoMessage() {}
+
+func (x *Policy) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Policy.ProtoReflect.Descriptor instead.
+func (*Policy) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Policy) GetKeys() []*PublicKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+func (x *Policy) GetNonce() uint32 {
+	if x != nil {
+		return x.Nonce
+	}
+	return 0
+}
+
+func (x *Policy) GetThreshold() int64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+type ChangeBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       string                 `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Delegates     []string               `protobuf:"bytes,2,rep,name=delegates,proto3" json:"delegates,omitempty"`
+	Previous      []byte                 `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangeBlock) Reset() {
+	*x = ChangeBlock{}
+	mi := &file_primitives_primitives_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangeBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangeBlock) ProtoMessage() {}
+
+func (x *ChangeBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangeBlock.ProtoReflect.Descriptor instead.
+func (*ChangeBlock) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChangeBlock) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *ChangeBlock) GetDelegates() []string {
+	if x != nil {
+		return x.Delegates
+	}
+	return nil
+}
+
+func (x *ChangeBlock) GetPrevious() []byte {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *ChangeBlock) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type DelegateBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       string                 `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Previous      []byte                 `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
+	Share         float64                `protobuf:"fixed64,3,opt,name=share,proto3" json:"share,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelegateBlock) Reset() {
+	*x = DelegateBlock{}
+	mi := &file_primitives_primitives_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelegateBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegateBlock) ProtoMessage() {}
+
+func (x *DelegateBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegateBlock.ProtoReflect.Descriptor instead.
+func (*DelegateBlock) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *DelegateBlock) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *DelegateBlock) GetPrevious() []byte {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *DelegateBlock) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *DelegateBlock) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type NameBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        uint32                 `protobuf:"varint,1,opt,name=action,proto3" json:"action,omitempty"`
+	Balance       string                 `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Destination   string                 `protobuf:"bytes,3,opt,name=destination,proto3" json:"destination,omitempty"`
+	Name          string                 `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	Previous      []byte                 `protobuf:"bytes,5,opt,name=previous,proto3" json:"previous,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NameBlock) Reset() {
+	*x = NameBlock{}
+	mi := &file_primitives_primitives_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NameBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NameBlock) ProtoMessage() {}
+
+func (x *NameBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NameBlock.ProtoReflect.Descriptor instead.
+func (*NameBlock) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *NameBlock) GetAction() uint32 {
+	if x != nil {
+		return x.Action
+	}
+	return 0
+}
+
+func (x *NameBlock) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *NameBlock) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *NameBlock) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *NameBlock) GetPrevious() []byte {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *NameBlock) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type OpenBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       string                 `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	Balance       string                 `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Key           *PublicKey             `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Policy        *Policy                `protobuf:"bytes,4,opt,name=policy,proto3" json:"policy,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenBlock) Reset() {
+	*x = OpenBlock{}
+	mi := &file_primitives_primitives_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenBlock) ProtoMessage() {}
+
+func (x *OpenBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenBlock.ProtoReflect.Descriptor instead.
+func (*OpenBlock) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OpenBlock) GetAccount() string {
+	if x != nil {
+		return x.Account
+	}
+	return ""
+}
+
+func (x *OpenBlock) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *OpenBlock) GetKey() *PublicKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *OpenBlock) GetPolicy() *Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+func (x *OpenBlock) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type ReceiveBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       string                 `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Previous      []byte                 `protobuf:"bytes,2,opt,name=previous,proto3" json:"previous,omitempty"`
+	Source        []byte                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiveBlock) Reset() {
+	*x = ReceiveBlock{}
+	mi := &file_primitives_primitives_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiveBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiveBlock) ProtoMessage() {}
+
+func (x *ReceiveBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiveBlock.ProtoReflect.Descriptor instead.
+func (*ReceiveBlock) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReceiveBlock) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *ReceiveBlock) GetPrevious() []byte {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *ReceiveBlock) GetSource() []byte {
+	if x != nil {
+		return x.Source
+	}
+	return nil
+}
+
+func (x *ReceiveBlock) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type RotateBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Authorization *Signature             `protobuf:"bytes,1,opt,name=authorization,proto3" json:"authorization,omitempty"`
+	Balance       string                 `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Key           *PublicKey             `protobuf:"bytes,3,opt,name=key,proto3" json:"key,omitempty"`
+	Previous      []byte                 `protobuf:"bytes,4,opt,name=previous,proto3" json:"previous,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RotateBlock) Reset() {
+	*x = RotateBlock{}
+	mi := &file_primitives_primitives_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RotateBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RotateBlock) ProtoMessage() {}
+
+func (x *RotateBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RotateBlock.ProtoReflect.Descriptor instead.
+func (*RotateBlock) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RotateBlock) GetAuthorization() *Signature {
+	if x != nil {
+		return x.Authorization
+	}
+	return nil
+}
+
+func (x *RotateBlock) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *RotateBlock) GetKey() *PublicKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *RotateBlock) GetPrevious() []byte {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *RotateBlock) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type SendBlock struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       string                 `protobuf:"bytes,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Destination   string                 `protobuf:"bytes,2,opt,name=destination,proto3" json:"destination,omitempty"`
+	Previous      []byte                 `protobuf:"bytes,3,opt,name=previous,proto3" json:"previous,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SendBlock) Reset() {
+	*x = SendBlock{}
+	mi := &file_primitives_primitives_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SendBlock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SendBlock) ProtoMessage() {}
+
+func (x *SendBlock) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SendBlock.ProtoReflect.Descriptor instead.
+func (*SendBlock) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SendBlock) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *SendBlock) GetDestination() string {
+	if x != nil {
+		return x.Destination
+	}
+	return ""
+}
+
+func (x *SendBlock) GetPrevious() []byte {
+	if x != nil {
+		return x.Previous
+	}
+	return nil
+}
+
+func (x *SendBlock) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+type Block struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Hash of the hashables which is checked when decoding if present
+	Hash         []byte       `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Cosignatures []*Signature `protobuf:"bytes,2,rep,name=cosignatures,proto3" json:"cosignatures,omitempty"`
+	Signature    *Signature   `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Witness      *Signature   `protobuf:"bytes,4,opt,name=witness,proto3" json:"witness,omitempty"`
+	// Types that are valid to be assigned to Hashables:
+	//
+	//	*Block_Change
+	//	*Block_Delegate
+	//	*Block_Name
+	//	*Block_Open
+	//	*Block_Receive
+	//	*Block_Rotate
+	//	*Block_Send
+	Hashables     isBlock_Hashables `protobuf_oneof:"hashables"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Block) Reset() {
+	*x = Block{}
+	mi := &file_primitives_primitives_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Block) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Block) ProtoMessage() {}
+
+func (x *Block) ProtoReflect() protoreflect.Message {
+	mi := &file_primitives_primitives_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Block.ProtoReflect.Descriptor instead.
+func (*Block) Descriptor() ([]byte, []int) {
+	return file_primitives_primitives_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Block) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *Block) GetCosignatures() []*Signature {
+	if x != nil {
+		return x.Cosignatures
+	}
+	return nil
+}
+
+func (x *Block) GetSignature() *Signature {
+	if x != nil {
+		return x.Signature
+	}
+	return nil
+}
+
+func (x *Block) GetWitness() *Signature {
+	if x != nil {
+		return x.Witness
+	}
+	return nil
+}
+
+func (x *Block) GetHashables() isBlock_Hashables {
+	if x != nil {
+		return x.Hashables
+	}
+	return nil
+}
+
+func (x *Block) GetChange() *ChangeBlock {
+	if x != nil {
+		if x, ok := x.Hashables.(*Block_Change); ok {
+			return x.Change
+		}
+	}
+	return nil
+}
+
+func (x *Block) GetDelegate() *DelegateBlock {
+	if x != nil {
+		if x, ok := x.Hashables.(*Block_Delegate); ok {
+			return x.Delegate
+		}
+	}
+	return nil
+}
+
+func (x *Block) GetName() *NameBlock {
+	if x != nil {
+		if x, ok := x.Hashables.(*Block_Name); ok {
+			return x.Name
+		}
+	}
+	return nil
+}
+
+func (x *Block) GetOpen() *OpenBlock {
+	if x != nil {
+		if x, ok := x.Hashables.(*Block_Open); ok {
+			return x.Open
+		}
+	}
+	return nil
+}
+
+func (x *Block) GetReceive() *ReceiveBlock {
+	if x != nil {
+		if x, ok := x.Hashables.(*Block_Receive); ok {
+			return x.Receive
+		}
+	}
+	return nil
+}
+
+func (x *Block) GetRotate() *RotateBlock {
+	if x != nil {
+		if x, ok := x.Hashables.(*Block_Rotate); ok {
+			return x.Rotate
+		}
+	}
+	return nil
+}
+
+func (x *Block) GetSend() *SendBlock {
+	if x != nil {
+		if x, ok := x.Hashables.(*Block_Send); ok {
+			return x.Send
+		}
+	}
+	return nil
+}
+
+type isBlock_Hashables interface {
+	isBlock_Hashables()
+}
+
+type Block_Change struct {
+	Change *ChangeBlock `protobuf:"bytes,5,opt,name=change,proto3,oneof"`
+}
+
+type Block_Delegate struct {
+	Delegate *DelegateBlock `protobuf:"bytes,6,opt,name=delegate,proto3,oneof"`
+}
+
+type Block_Name struct {
+	Name *NameBlock `protobuf:"bytes,7,opt,name=name,proto3,oneof"`
+}
+
+type Block_Open struct {
+	Open *OpenBlock `protobuf:"bytes,8,opt,name=open,proto3,oneof"`
+}
+
+type Block_Receive struct {
+	Receive *ReceiveBlock `protobuf:"bytes,9,opt,name=receive,proto3,oneof"`
+}
+
+type Block_Rotate struct {
+	Rotate *RotateBlock `protobuf:"bytes,10,opt,name=rotate,proto3,oneof"`
+}
+
+type Block_Send struct {
+	Send *SendBlock `protobuf:"bytes,11,opt,name=send,proto3,oneof"`
+}
+
+func (*Block_Change) isBlock_Hashables() {}
+
+func (*Block_Delegate) isBlock_Hashables() {}
+
+func (*Block_Name) isBlock_Hashables() {}
+
+func (*Block_Open) isBlock_Hashables() {}
+
+func (*Block_Receive) isBlock_Hashables() {}
+
+func (*Block_Rotate) isBlock_Hashables() {}
+
+func (*Block_Send) isBlock_Hashables() {}
+
+var File_primitives_primitives_proto protoreflect.FileDescriptor
+
+const file_primitives_primitives_proto_rawDesc = "" +
+	"\n" +
+	"\x1bprimitives/primitives.proto\x12\x13watchmen.primitives\"3\n" +
+	"\tSignature\x12\x0e\n" +
+	"\x02rs\x18\x01 \x01(\fR\x02rs\x12\x16\n" +
+	"\x06scheme\x18\x02 \x01(\rR\x06scheme\"9\n" +
+	"\tPublicKey\x12\x14\n" +
+	"\x05bytes\x18\x01 \x01(\fR\x05bytes\x12\x16\n" +
+	"\x06scheme\x18\x02 \x01(\rR\x06scheme\"p\n" +
+	"\x06Policy\x122\n" +
+	"\x04keys\x18\x01 \x03(\v2\x1e.watchmen.primitives.PublicKeyR\x04keys\x12\x14\n" +
+	"\x05nonce\x18\x02 \x01(\rR\x05nonce\x12\x1c\n" +
+	"\tthreshold\x18\x03 \x01(\x03R\tthreshold\"\x7f\n" +
+	"\vChangeBlock\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\tR\abalance\x12\x1c\n" +
+	"\tdelegates\x18\x02 \x03(\tR\tdelegates\x12\x1a\n" +
+	"\bprevious\x18\x03 \x01(\fR\bprevious\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\"y\n" +
+	"\rDelegateBlock\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\tR\abalance\x12\x1a\n" +
+	"\bprevious\x18\x02 \x01(\fR\bprevious\x12\x14\n" +
+	"\x05share\x18\x03 \x01(\x01R\x05share\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\"\xad\x01\n" +
+	"\tNameBlock\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\rR\x06action\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\tR\abalance\x12 \n" +
+	"\vdestination\x18\x03 \x01(\tR\vdestination\x12\x12\n" +
+	"\x04name\x18\x04 \x01(\tR\x04name\x12\x1a\n" +
+	"\bprevious\x18\x05 \x01(\fR\bprevious\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"\xc4\x01\n" +
+	"\tOpenBlock\x12\x18\n" +
+	"\aaccount\x18\x01 \x01(\tR\aaccount\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\tR\abalance\x120\n" +
+	"\x03key\x18\x03 \x01(\v2\x1e.watchmen.primitives.PublicKeyR\x03key\x123\n" +
+	"\x06policy\x18\x04 \x01(\v2\x1b.watchmen.primitives.PolicyR\x06policy\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"z\n" +
+	"\fReceiveBlock\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\tR\abalance\x12\x1a\n" +
+	"\bprevious\x18\x02 \x01(\fR\bprevious\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\fR\x06source\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\"\xd9\x01\n" +
+	"\vRotateBlock\x12D\n" +
+	"\rauthorization\x18\x01 \x01(\v2\x1e.watchmen.primitives.SignatureR\rauthorization\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\tR\abalance\x120\n" +
+	"\x03key\x18\x03 \x01(\v2\x1e.watchmen.primitives.PublicKeyR\x03key\x12\x1a\n" +
+	"\bprevious\x18\x04 \x01(\fR\bprevious\x12\x1c\n" +
+	"\ttimestamp\x18\x05 \x01(\x03R\ttimestamp\"\x81\x01\n" +
+	"\tSendBlock\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\tR\abalance\x12 \n" +
+	"\vdestination\x18\x02 \x01(\tR\vdestination\x12\x1a\n" +
+	"\bprevious\x18\x03 \x01(\fR\bprevious\x12\x1c\n" +
+	"\ttimestamp\x18\x04 \x01(\x03R\ttimestamp\"\xff\x04\n" +
+	"\x05Block\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\fR\x04hash\x12B\n" +
+	"\fcosignatures\x18\x02 \x03(\v2\x1e.watchmen.primitives.SignatureR\fcosignatures\x12<\n" +
+	"\tsignature\x18\x03 \x01(\v2\x1e.watchmen.primitives.SignatureR\tsignature\x128\n" +
+	"\awitness\x18\x04 \x01(\v2\x1e.watchmen.primitives.SignatureR\awitness\x12:\n" +
+	"\x06change\x18\x05 \x01(\v2 .watchmen.primitives.ChangeBlockH\x00R\x06change\x12@\n" +
+	"\bdelegate\x18\x06 \x01(\v2\".watchmen.primitives.DelegateBlockH\x00R\bdelegate\x124\n" +
+	"\x04name\x18\a \x01(\v2\x1e.watchmen.primitives.NameBlockH\x00R\x04name\x124\n" +
+	"\x04open\x18\b \x01(\v2\x1e.watchmen.primitives.OpenBlockH\x00R\x04open\x12=\n" +
+	"\areceive\x18\t \x01(\v2!.watchmen.primitives.ReceiveBlockH\x00R\areceive\x12:\n" +
+	"\x06rotate\x18\n" +
+	" \x01(\v2 .watchmen.primitives.RotateBlockH\x00R\x06rotate\x124\n" +
+	"\x04send\x18\v \x01(\v2\x1e.watchmen.primitives.SendBlockH\x00R\x04sendB\v\n" +
+	"\thashablesB5Z3github.com/kookehs/watchmen/primitives/primitivespbb\x06proto3"
+
+var (
+	file_primitives_primitives_proto_rawDescOnce sync.Once
+	file_primitives_primitives_proto_rawDescData []byte
+)
+
+func file_primitives_primitives_proto_rawDescGZIP() []byte {
+	file_primitives_primitives_proto_rawDescOnce.Do(func() {
+		file_primitives_primitives_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_primitives_primitives_proto_rawDesc), len(file_primitives_primitives_proto_rawDesc)))
+	})
+	return file_primitives_primitives_proto_rawDescData
+}
+
+var file_primitives_primitives_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_primitives_primitives_proto_goTypes = []any{
+	(*Signature)(nil),     // 0: watchmen.primitives.Signature
+	(*PublicKey)(nil),     // 1: watchmen.primitives.PublicKey
+	(*Policy)(nil),        // 2: watchmen.primitives.Policy
+	(*ChangeBlock)(nil),   // 3: watchmen.primitives.ChangeBlock
+	(*DelegateBlock)(nil), // 4: watchmen.primitives.DelegateBlock
+	(*NameBlock)(nil),     // 5: watchmen.primitives.NameBlock
+	(*OpenBlock)(nil),     // 6: watchmen.primitives.OpenBlock
+	(*ReceiveBlock)(nil),  // 7: watchmen.primitives.ReceiveBlock
+	(*RotateBlock)(nil),   // 8: watchmen.primitives.RotateBlock
+	(*SendBlock)(nil),     // 9: watchmen.primitives.SendBlock
+	(*Block)(nil),         // 10: watchmen.primitives.Block
+}
+var file_primitives_primitives_proto_depIdxs = []int32{
+	1,  // 0: watchmen.primitives.Policy.keys:type_name -> watchmen.primitives.PublicKey
+	1,  // 1: watchmen.primitives.OpenBlock.key:type_name -> watchmen.primitives.PublicKey
+	2,  // 2: watchmen.primitives.OpenBlock.policy:type_name -> watchmen.primitives.Policy
+	0,  // 3: watchmen.primitives.RotateBlock.authorization:type_name -> watchmen.primitives.Signature
+	1,  // 4: watchmen.primitives.RotateBlock.key:type_name -> watchmen.primitives.PublicKey
+	0,  // 5: watchmen.primitives.Block.cosignatures:type_name -> watchmen.primitives.Signature
+	0,  // 6: watchmen.primitives.Block.signature:type_name -> watchmen.primitives.Signature
+	0,  // 7: watchmen.primitives.Block.witness:type_name -> watchmen.primitives.Signature
+	3,  // 8: watchmen.primitives.Block.change:type_name -> watchmen.primitives.ChangeBlock
+	4,  // 9: watchmen.primitives.Block.delegate:type_name -> watchmen.primitives.DelegateBlock
+	5,  // 10: watchmen.primitives.Block.name:type_name -> watchmen.primitives.NameBlock
+	6,  // 11: watchmen.primitives.Block.open:type_name -> watchmen.primitives.OpenBlock
+	7,  // 12: watchmen.primitives.Block.receive:type_name -> watchmen.primitives.ReceiveBlock
+	8,  // 13: watchmen.primitives.Block.rotate:type_name -> watchmen.primitives.RotateBlock
+	9,  // 14: watchmen.primitives.Block.send:type_name -> watchmen.primitives.SendBlock
+	15, // [15:15] is the sub-list for method output_type
+	15, // [15:15] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_primitives_primitives_proto_init() }
+func file_primitives_primitives_proto_init() {
+	if File_primitives_primitives_proto != nil {
+		return
+	}
+	file_primitives_primitives_proto_msgTypes[10].OneofWrappers = []any{
+		(*Block_Change)(nil),
+		(*Block_Delegate)(nil),
+		(*Block_Name)(nil),
+		(*Block_Open)(nil),
+		(*Block_Receive)(nil),
+		(*Block_Rotate)(nil),
+		(*Block_Send)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_primitives_primitives_proto_rawDesc), len(file_primitives_primitives_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   11,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_primitives_primitives_proto_goTypes,
+		DependencyIndexes: file_primitives_primitives_proto_depIdxs,
+		MessageInfos:      file_primitives_primitives_proto_msgTypes,
+	}.Build()
+	File_primitives_primitives_proto = out.File
+	file_primitives_primitives_proto_goTypes = nil
+	file_primitives_primitives_proto_depIdxs = nil
+}
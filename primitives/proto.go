@@ -0,0 +1,697 @@
+package primitives
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/kookehs/watchmen/crypto"
+	"github.com/kookehs/watchmen/primitives/primitivespb"
+	"google.golang.org/protobuf/proto"
+)
+
+// Blocks are converted to and from the messages generated from primitives.proto in primitivespb.
+// Messages are marshaled deterministically so that the same block always encodes to the same bytes.
+var protoMarshal = proto.MarshalOptions{Deterministic: true}
+
+// BlockFromProto returns a block of the type contained in the given Block message.
+// The hash in the message is checked against the decoded block if present.
+func BlockFromProto(message *primitivespb.Block) (Block, error) {
+	d := &protoDecoder{}
+	var cosignatures []Signature
+
+	for _, cosignature := range message.GetCosignatures() {
+		cosignatures = append(cosignatures, d.signature(cosignature))
+	}
+
+	signature := d.signature(message.GetSignature())
+	witness := d.signature(message.GetWitness())
+	var block Block
+
+	switch hashables := message.GetHashables().(type) {
+	case *primitivespb.Block_Change:
+		h := hashables.Change
+		var delegates []IBAN
+
+		for _, delegate := range h.GetDelegates() {
+			delegates = append(delegates, d.iban(delegate))
+		}
+
+		block = &ChangeBlock{
+			Cosignatures: cosignatures,
+			Hashables: ChangeHashables{
+				Balance:   d.amount(h.GetBalance()),
+				Delegates: delegates,
+				Previous:  d.hash(h.GetPrevious()),
+				Timestamp: h.GetTimestamp(),
+				Type:      Change,
+			},
+			Signature: signature,
+			Witness:   witness,
+		}
+	case *primitivespb.Block_Delegate:
+		h := hashables.Delegate
+		block = &DelegateBlock{
+			Cosignatures: cosignatures,
+			Hashables: DelegateHashables{
+				Balance:   d.amount(h.GetBalance()),
+				Previous:  d.hash(h.GetPrevious()),
+				Share:     h.GetShare(),
+				Timestamp: h.GetTimestamp(),
+				Type:      Delegate,
+			},
+			Signature: signature,
+			Witness:   witness,
+		}
+	case *primitivespb.Block_Name:
+		h := hashables.Name
+		block = &NameBlock{
+			Cosignatures: cosignatures,
+			Hashables: NameHashables{
+				Action:      NameAction(d.uint8(h.GetAction(), "Name action")),
+				Balance:     d.amount(h.GetBalance()),
+				Destination: d.iban(h.GetDestination()),
+				Name:        h.GetName(),
+				Previous:    d.hash(h.GetPrevious()),
+				Timestamp:   h.GetTimestamp(),
+				Type:        Name,
+			},
+			Signature: signature,
+			Witness:   witness,
+		}
+	case *primitivespb.Block_Open:
+		h := hashables.Open
+		open := &OpenBlock{
+			Cosignatures: cosignatures,
+			Hashables: OpenHashables{
+				Account:   d.iban(h.GetAccount()),
+				Balance:   d.amount(h.GetBalance()),
+				Timestamp: h.GetTimestamp(),
+				Type:      Open,
+			},
+			Signature: signature,
+			Witness:   witness,
+		}
+
+		if h.GetKey() != nil {
+			key := d.key(h.GetKey())
+			open.Hashables.Key = &key
+		}
+
+		if h.GetPolicy() != nil {
+			policy := d.policy(h.GetPolicy())
+			open.Hashables.Policy = &policy
+		}
+
+		block = open
+	case *primitivespb.Block_Receive:
+		h := hashables.Receive
+		block = &ReceiveBlock{
+			Cosignatures: cosignatures,
+			Hashables: ReceiveHashables{
+				Balance:   d.amount(h.GetBalance()),
+				Previous:  d.hash(h.GetPrevious()),
+				Source:    d.hash(h.GetSource()),
+				Timestamp: h.GetTimestamp(),
+				Type:      Receive,
+			},
+			Signature: signature,
+			Witness:   witness,
+		}
+	case *primitivespb.Block_Rotate:
+		h := hashables.Rotate
+		block = &RotateBlock{
+			Cosignatures: cosignatures,
+			Hashables: RotateHashables{
+				Authorization: d.signature(h.GetAuthorization()),
+				Balance:       d.amount(h.GetBalance()),
+				Key:           d.key(h.GetKey()),
+				Previous:      d.hash(h.GetPrevious()),
+				Timestamp:     h.GetTimestamp(),
+				Type:          Rotate,
+			},
+			Signature: signature,
+			Witness:   witness,
+		}
+	case *primitivespb.Block_Send:
+		h := hashables.Send
+		block = &SendBlock{
+			Cosignatures: cosignatures,
+			Hashables: SendHashables{
+				Balance:     d.amount(h.GetBalance()),
+				Destination: d.iban(h.GetDestination()),
+				Previous:    d.hash(h.GetPrevious()),
+				Timestamp:   h.GetTimestamp(),
+				Type:        Send,
+			},
+			Signature: signature,
+			Witness:   witness,
+		}
+	default:
+		return nil, errors.New("Block message does not contain hashables")
+	}
+
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	if message.GetHash() == nil {
+		return block, nil
+	}
+
+	if len(message.GetHash()) != len(BlockHashZero) {
+		return nil, fmt.Errorf("Block hash must be %v bytes", len(BlockHashZero))
+	}
+
+	hash, err := block.Hash()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(hash[:], message.GetHash()) {
+		return nil, errors.New("Block hash does not match its contents")
+	}
+
+	return block, nil
+}
+
+// BlockToProto returns the Block message of the given block including its hash.
+func BlockToProto(block Block) (*primitivespb.Block, error) {
+	hash, err := block.Hash()
+
+	if err != nil {
+		return nil, err
+	}
+
+	var cosignatures []Signature
+	var signature, witness Signature
+	message := &primitivespb.Block{Hash: hash[:]}
+
+	switch b := block.(type) {
+	case *ChangeBlock:
+		delegates := make([]string, 0, len(b.Hashables.Delegates))
+
+		for i := range b.Hashables.Delegates {
+			delegates = append(delegates, protoIBAN(b.Hashables.Delegates[i]))
+		}
+
+		message.Hashables = &primitivespb.Block_Change{
+			Change: &primitivespb.ChangeBlock{
+				Balance:   protoAmount(b.Hashables.Balance),
+				Delegates: delegates,
+				Previous:  protoHash(b.Hashables.Previous),
+				Timestamp: b.Hashables.Timestamp,
+			},
+		}
+
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *DelegateBlock:
+		message.Hashables = &primitivespb.Block_Delegate{
+			Delegate: &primitivespb.DelegateBlock{
+				Balance:   protoAmount(b.Hashables.Balance),
+				Previous:  protoHash(b.Hashables.Previous),
+				Share:     b.Hashables.Share,
+				Timestamp: b.Hashables.Timestamp,
+			},
+		}
+
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *NameBlock:
+		message.Hashables = &primitivespb.Block_Name{
+			Name: &primitivespb.NameBlock{
+				Action:      uint32(b.Hashables.Action),
+				Balance:     protoAmount(b.Hashables.Balance),
+				Destination: protoIBAN(b.Hashables.Destination),
+				Name:        b.Hashables.Name,
+				Previous:    protoHash(b.Hashables.Previous),
+				Timestamp:   b.Hashables.Timestamp,
+			},
+		}
+
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *OpenBlock:
+		open := &primitivespb.OpenBlock{
+			Account:   protoIBAN(b.Hashables.Account),
+			Balance:   protoAmount(b.Hashables.Balance),
+			Timestamp: b.Hashables.Timestamp,
+		}
+
+		if b.Hashables.Key != nil {
+			open.Key = b.Hashables.Key.Proto()
+		}
+
+		if b.Hashables.Policy != nil {
+			open.Policy = b.Hashables.Policy.Proto()
+		}
+
+		message.Hashables = &primitivespb.Block_Open{Open: open}
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *ReceiveBlock:
+		message.Hashables = &primitivespb.Block_Receive{
+			Receive: &primitivespb.ReceiveBlock{
+				Balance:   protoAmount(b.Hashables.Balance),
+				Previous:  protoHash(b.Hashables.Previous),
+				Source:    protoHash(b.Hashables.Source),
+				Timestamp: b.Hashables.Timestamp,
+			},
+		}
+
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *RotateBlock:
+		message.Hashables = &primitivespb.Block_Rotate{
+			Rotate: &primitivespb.RotateBlock{
+				Authorization: b.Hashables.Authorization.Proto(),
+				Balance:       protoAmount(b.Hashables.Balance),
+				Key:           b.Hashables.Key.Proto(),
+				Previous:      protoHash(b.Hashables.Previous),
+				Timestamp:     b.Hashables.Timestamp,
+			},
+		}
+
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	case *SendBlock:
+		message.Hashables = &primitivespb.Block_Send{
+			Send: &primitivespb.SendBlock{
+				Balance:     protoAmount(b.Hashables.Balance),
+				Destination: protoIBAN(b.Hashables.Destination),
+				Previous:    protoHash(b.Hashables.Previous),
+				Timestamp:   b.Hashables.Timestamp,
+			},
+		}
+
+		cosignatures, signature, witness = b.Cosignatures, b.Signature, b.Witness
+	default:
+		return nil, fmt.Errorf("Unknown block type %T", block)
+	}
+
+	for i := range cosignatures {
+		message.Cosignatures = append(message.Cosignatures, cosignatures[i].Proto())
+	}
+
+	message.Signature = signature.Proto()
+	message.Witness = witness.Proto()
+	return message, nil
+}
+
+// DecodeBlockProto decodes a Block message into a block of the type it contains.
+func DecodeBlockProto(b []byte) (Block, error) {
+	message := new(primitivespb.Block)
+
+	if err := proto.Unmarshal(b, message); err != nil {
+		return nil, err
+	}
+
+	return BlockFromProto(message)
+}
+
+// marshalProtoBlock encodes the given block as a Block message.
+func marshalProtoBlock(block Block) ([]byte, error) {
+	message, err := BlockToProto(block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return protoMarshal.Marshal(message)
+}
+
+// unmarshalProtoBlock decodes a Block message that must contain a block of the given type.
+func unmarshalProtoBlock(b []byte, t BlockType) (Block, error) {
+	block, err := DecodeBlockProto(b)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if block.Type() != t {
+		return nil, fmt.Errorf("Block message contains a %v block instead of a %v block", block.Type(), t)
+	}
+
+	return block, nil
+}
+
+// MarshalProto encodes the ChangeBlock as a Block message.
+func (cb *ChangeBlock) MarshalProto() ([]byte, error) {
+	return marshalProtoBlock(cb)
+}
+
+// UnmarshalProto decodes a Block message containing a ChangeBlock.
+func (cb *ChangeBlock) UnmarshalProto(b []byte) error {
+	block, err := unmarshalProtoBlock(b, Change)
+
+	if err != nil {
+		return err
+	}
+
+	*cb = *block.(*ChangeBlock)
+	return nil
+}
+
+// MarshalProto encodes the DelegateBlock as a Block message.
+func (db *DelegateBlock) MarshalProto() ([]byte, error) {
+	return marshalProtoBlock(db)
+}
+
+// UnmarshalProto decodes a Block message containing a DelegateBlock.
+func (db *DelegateBlock) UnmarshalProto(b []byte) error {
+	block, err := unmarshalProtoBlock(b, Delegate)
+
+	if err != nil {
+		return err
+	}
+
+	*db = *block.(*DelegateBlock)
+	return nil
+}
+
+// MarshalProto encodes the NameBlock as a Block message.
+func (nb *NameBlock) MarshalProto() ([]byte, error) {
+	return marshalProtoBlock(nb)
+}
+
+// UnmarshalProto decodes a Block message containing a NameBlock.
+func (nb *NameBlock) UnmarshalProto(b []byte) error {
+	block, err := unmarshalProtoBlock(b, Name)
+
+	if err != nil {
+		return err
+	}
+
+	*nb = *block.(*NameBlock)
+	return nil
+}
+
+// MarshalProto encodes the OpenBlock as a Block message.
+func (ob *OpenBlock) MarshalProto() ([]byte, error) {
+	return marshalProtoBlock(ob)
+}
+
+// UnmarshalProto decodes a Block message containing an OpenBlock.
+func (ob *OpenBlock) UnmarshalProto(b []byte) error {
+	block, err := unmarshalProtoBlock(b, Open)
+
+	if err != nil {
+		return err
+	}
+
+	*ob = *block.(*OpenBlock)
+	return nil
+}
+
+// MarshalProto encodes the ReceiveBlock as a Block message.
+func (rb *ReceiveBlock) MarshalProto() ([]byte, error) {
+	return marshalProtoBlock(rb)
+}
+
+// UnmarshalProto decodes a Block message containing a ReceiveBlock.
+func (rb *ReceiveBlock) UnmarshalProto(b []byte) error {
+	block, err := unmarshalProtoBlock(b, Receive)
+
+	if err != nil {
+		return err
+	}
+
+	*rb = *block.(*ReceiveBlock)
+	return nil
+}
+
+// MarshalProto encodes the RotateBlock as a Block message.
+func (rob *RotateBlock) MarshalProto() ([]byte, error) {
+	return marshalProtoBlock(rob)
+}
+
+// UnmarshalProto decodes a Block message containing a RotateBlock.
+func (rob *RotateBlock) UnmarshalProto(b []byte) error {
+	block, err := unmarshalProtoBlock(b, Rotate)
+
+	if err != nil {
+		return err
+	}
+
+	*rob = *block.(*RotateBlock)
+	return nil
+}
+
+// MarshalProto encodes the SendBlock as a Block message.
+func (sb *SendBlock) MarshalProto() ([]byte, error) {
+	return marshalProtoBlock(sb)
+}
+
+// UnmarshalProto decodes a Block message containing a SendBlock.
+func (sb *SendBlock) UnmarshalProto(b []byte) error {
+	block, err := unmarshalProtoBlock(b, Send)
+
+	if err != nil {
+		return err
+	}
+
+	*sb = *block.(*SendBlock)
+	return nil
+}
+
+// Proto returns the Policy message of the Policy.
+func (p *Policy) Proto() *primitivespb.Policy {
+	message := &primitivespb.Policy{
+		Nonce:     p.Nonce,
+		Threshold: int64(p.Threshold),
+	}
+
+	for i := range p.Keys {
+		message.Keys = append(message.Keys, p.Keys[i].Proto())
+	}
+
+	return message
+}
+
+// MarshalProto encodes the Policy as a Policy message.
+func (p *Policy) MarshalProto() []byte {
+	b, _ := protoMarshal.Marshal(p.Proto())
+	return b
+}
+
+// UnmarshalProto decodes a Policy message.
+func (p *Policy) UnmarshalProto(b []byte) error {
+	message := new(primitivespb.Policy)
+
+	if err := proto.Unmarshal(b, message); err != nil {
+		return err
+	}
+
+	d := &protoDecoder{}
+	decoded := d.policy(message)
+
+	if d.err != nil {
+		return d.err
+	}
+
+	*p = decoded
+	return nil
+}
+
+// Proto returns the PublicKey message of the PublicKey.
+func (pk *PublicKey) Proto() *primitivespb.PublicKey {
+	return &primitivespb.PublicKey{
+		Bytes:  bytes.Clone(pk.Bytes),
+		Scheme: uint32(pk.Scheme),
+	}
+}
+
+// MarshalProto encodes the PublicKey as a PublicKey message.
+func (pk *PublicKey) MarshalProto() []byte {
+	b, _ := protoMarshal.Marshal(pk.Proto())
+	return b
+}
+
+// UnmarshalProto decodes a PublicKey message.
+func (pk *PublicKey) UnmarshalProto(b []byte) error {
+	message := new(primitivespb.PublicKey)
+
+	if err := proto.Unmarshal(b, message); err != nil {
+		return err
+	}
+
+	d := &protoDecoder{}
+	decoded := d.key(message)
+
+	if d.err != nil {
+		return d.err
+	}
+
+	*pk = decoded
+	return nil
+}
+
+// Proto returns the Signature message of the Signature.
+func (s *Signature) Proto() *primitivespb.Signature {
+	return &primitivespb.Signature{
+		Rs:     bytes.Clone(s.RS[:]),
+		Scheme: uint32(s.Scheme),
+	}
+}
+
+// MarshalProto encodes the Signature as a Signature message.
+func (s *Signature) MarshalProto() []byte {
+	b, _ := protoMarshal.Marshal(s.Proto())
+	return b
+}
+
+// UnmarshalProto decodes a Signature message.
+func (s *Signature) UnmarshalProto(b []byte) error {
+	message := new(primitivespb.Signature)
+
+	if err := proto.Unmarshal(b, message); err != nil {
+		return err
+	}
+
+	d := &protoDecoder{}
+	decoded := d.signature(message)
+
+	if d.err != nil {
+		return d.err
+	}
+
+	*s = decoded
+	return nil
+}
+
+// protoAmount returns the given Amount as a decimal string or an empty string if it is nil.
+// The shortest decimal that parses back to the same value at AmountPrecision is used.
+func protoAmount(amount Amount) string {
+	if amount == nil {
+		return ""
+	}
+
+	return amount.Text('g', -1)
+}
+
+// protoHash returns a copy of the bytes of the given BlockHash.
+func protoHash(hash BlockHash) []byte {
+	return hash[:]
+}
+
+// protoIBAN returns the electronic format of the given IBAN or an empty string if it is zero.
+func protoIBAN(iban IBAN) string {
+	if iban == (IBAN{}) {
+		return ""
+	}
+
+	return iban.String()
+}
+
+// protoDecoder converts the fields of generated messages keeping the first error encountered.
+type protoDecoder struct {
+	err error
+}
+
+// fail records the given error unless an error has already been recorded.
+func (d *protoDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// amount decodes an Amount from its decimal string or returns nil if it is empty.
+// Parsing at AmountPrecision and normalizing reproduces the amount that was hashed.
+func (d *protoDecoder) amount(s string) Amount {
+	if s == "" {
+		return nil
+	}
+
+	amount, ok := new(big.Float).SetPrec(AmountPrecision).SetString(s)
+
+	if !ok || amount.IsInf() {
+		d.fail(fmt.Errorf("Invalid amount %q", s))
+		return nil
+	}
+
+	return NormalizeAmount(amount)
+}
+
+// hash decodes a BlockHash which is zero if b is empty.
+func (d *protoDecoder) hash(b []byte) BlockHash {
+	var hash BlockHash
+
+	if len(b) == 0 {
+		return hash
+	}
+
+	if len(b) != len(hash) {
+		d.fail(fmt.Errorf("Block hash must be %v bytes", len(hash)))
+		return hash
+	}
+
+	copy(hash[:], b)
+	return hash
+}
+
+// iban decodes an IBAN from its electronic format which is zero if s is empty.
+func (d *protoDecoder) iban(s string) IBAN {
+	var iban IBAN
+
+	if s == "" {
+		return iban
+	}
+
+	if len(s) != IBANSize {
+		d.fail(fmt.Errorf("IBAN must be %v characters", IBANSize))
+		return iban
+	}
+
+	copy(iban[:], s)
+	return iban
+}
+
+// key decodes a PublicKey message.
+func (d *protoDecoder) key(message *primitivespb.PublicKey) PublicKey {
+	return PublicKey{
+		Bytes:  bytes.Clone(message.GetBytes()),
+		Scheme: crypto.Scheme(d.uint8(message.GetScheme(), "Key scheme")),
+	}
+}
+
+// policy decodes a Policy message.
+func (d *protoDecoder) policy(message *primitivespb.Policy) Policy {
+	policy := Policy{
+		Nonce:     message.GetNonce(),
+		Threshold: int(message.GetThreshold()),
+	}
+
+	for _, key := range message.GetKeys() {
+		policy.Keys = append(policy.Keys, d.key(key))
+	}
+
+	return policy
+}
+
+// signature decodes a Signature message which is zero if the message is nil.
+func (d *protoDecoder) signature(message *primitivespb.Signature) Signature {
+	var signature Signature
+
+	if message == nil {
+		return signature
+	}
+
+	if rs := message.GetRs(); len(rs) != 0 {
+		if len(rs) != SignatureSize {
+			d.fail(fmt.Errorf("Signature must be %v bytes", SignatureSize))
+			return signature
+		}
+
+		copy(signature.RS[:], rs)
+	}
+
+	signature.Scheme = crypto.Scheme(d.uint8(message.GetScheme(), "Signature scheme"))
+	return signature
+}
+
+// uint8 decodes a field that must fit in a byte.
+func (d *protoDecoder) uint8(v uint32, name string) uint8 {
+	if v > 0xFF {
+		d.fail(fmt.Errorf("%v %v overflows a byte", name, v))
+		return 0
+	}
+
+	return uint8(v)
+}
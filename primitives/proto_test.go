@@ -0,0 +1,355 @@
+package primitives
+
+import (
+	"bytes"
+	"crypto/rand"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/kookehs/watchmen/crypto"
+	"github.com/kookehs/watchmen/primitives/primitivespb"
+	"google.golang.org/protobuf/proto"
+)
+
+func mustSigner(t *testing.T, scheme crypto.Scheme) crypto.Signer {
+	t.Helper()
+	signer, err := crypto.GenerateSigner(scheme, rand.Reader)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signer
+}
+
+func mustIBAN(t *testing.T, s string) IBAN {
+	t.Helper()
+	iban, err := ParseIBAN(s)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return iban
+}
+
+// third returns 1/3 which needs every bit of AmountPrecision.
+func third() Amount {
+	return new(big.Float).SetPrec(AmountPrecision).Quo(NewAmount(1), NewAmount(3))
+}
+
+// testBlock is a signed block of each type along with the balance its Block message must contain.
+type testBlock struct {
+	block   Block
+	balance string
+}
+
+// testBlocks returns a signed block of every type.
+// Blocks are signed with P-256 and Ed25519 keys and the OpenBlock of a Policy carries cosignatures.
+func testBlocks(t *testing.T) []testBlock {
+	t.Helper()
+	p256 := mustSigner(t, crypto.P256)
+	ed25519 := mustSigner(t, crypto.Ed25519)
+	source := mustIBAN(t, "TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZS")
+	destination := mustIBAN(t, "TV28ZZZZZZZZZZZZZZZZZZZZZZZZZZZZZZ")
+	prev := BlockHash{1, 2, 3}
+
+	key := MakePublicKey(p256.Verifier())
+	policy, err := NewPolicy(2, []crypto.Verifier{p256.Verifier(), ed25519.Verifier()})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := MakePublicKey(ed25519.Verifier())
+	authorization, err := SignHash(RotationHash(rotated, prev), p256)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large, _ := new(big.Float).SetString("1e+21")
+	blocks := []testBlock{
+		{NewChangeBlock(NewAmount(12.5), []IBAN{source, destination}, prev), "12.5"},
+		{NewDelegateBlock(third(), prev, 37.5), "0.3333333333333333"},
+		{NewNameBlock(NewAmount(0.1), TransferName, "alice", destination, prev), "0.1"},
+		{NewOpenBlock(NewAmount(0), source, &key), "0"},
+		{NewMultisigOpenBlock(large, source, policy), "1e+21"},
+		{NewReceiveBlock(NewAmount(1e-9), prev, BlockHash{4, 5, 6}), "1e-09"},
+		{NewRotateBlock(NewAmount(100), rotated, authorization, prev), "100"},
+		{NewSendBlock(NewAmount(89.9), destination, prev), "89.9"},
+	}
+
+	for i, test := range blocks {
+		signer := p256
+
+		if i%2 == 1 {
+			signer = ed25519
+		}
+
+		if err := test.block.Sign(signer); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := test.block.SignWitness(p256); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	multisig := blocks[4].block
+
+	for _, signer := range []crypto.Signer{p256, ed25519} {
+		if err := multisig.Cosign(signer); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return blocks
+}
+
+// checkSameBlock reports differences between a block and the block it was decoded into.
+func checkSameBlock(t *testing.T, got, want Block) {
+	t.Helper()
+
+	if got.Type() != want.Type() {
+		t.Fatalf("Type = %v, want %v", got.Type(), want.Type())
+	}
+
+	gotHash, err := got.Hash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantHash, err := want.Hash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if gotHash != wantHash {
+		t.Errorf("%v hash = %v, want %v", want.Type(), gotHash, wantHash)
+	}
+
+	if got.Balance().Cmp(want.Balance()) != 0 || (got.Balance().Prec() != AmountPrecision) {
+		t.Errorf("%v balance = %v at %v bits, want %v", want.Type(), got.Balance(), got.Balance().Prec(), want.Balance())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("%v = %+v, want %+v", want.Type(), got, want)
+	}
+}
+
+func TestBlockProtoRoundTrip(t *testing.T) {
+	for _, test := range testBlocks(t) {
+		b, err := test.block.MarshalProto()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		decoded, err := DecodeBlockProto(b)
+
+		if err != nil {
+			t.Fatalf("DecodeBlockProto of %v: %v", test.block.Type(), err)
+		}
+
+		checkSameBlock(t, decoded, test.block)
+		again, err := decoded.MarshalProto()
+
+		if (err != nil) || !bytes.Equal(again, b) {
+			t.Errorf("MarshalProto of decoded %v = %x, %v, want %x", test.block.Type(), again, err, b)
+		}
+
+		// The message must be readable by code generated from primitives.proto.
+		message := new(primitivespb.Block)
+
+		if err := proto.Unmarshal(b, message); err != nil {
+			t.Fatalf("Unmarshal %v into primitivespb.Block: %v", test.block.Type(), err)
+		}
+
+		if balance := protoBalance(message); balance != test.balance {
+			t.Errorf("%v balance field = %q, want %q", test.block.Type(), balance, test.balance)
+		}
+
+		generated, err := proto.MarshalOptions{Deterministic: true}.Marshal(message)
+
+		if (err != nil) || !bytes.Equal(generated, b) {
+			t.Errorf("Generated encoding of %v differs: %x, %v, want %x", test.block.Type(), generated, err, b)
+		}
+	}
+}
+
+// protoBalance returns the balance field of the hashables in the given message.
+func protoBalance(message *primitivespb.Block) string {
+	switch hashables := message.GetHashables().(type) {
+	case *primitivespb.Block_Change:
+		return hashables.Change.GetBalance()
+	case *primitivespb.Block_Delegate:
+		return hashables.Delegate.GetBalance()
+	case *primitivespb.Block_Name:
+		return hashables.Name.GetBalance()
+	case *primitivespb.Block_Open:
+		return hashables.Open.GetBalance()
+	case *primitivespb.Block_Receive:
+		return hashables.Receive.GetBalance()
+	case *primitivespb.Block_Rotate:
+		return hashables.Rotate.GetBalance()
+	case *primitivespb.Block_Send:
+		return hashables.Send.GetBalance()
+	default:
+		return ""
+	}
+}
+
+func TestBlockProtoAbsentBalance(t *testing.T) {
+	block := NewSendBlock(NewAmount(1), mustIBAN(t, "TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZS"), BlockHash{1})
+	block.Hashables.Balance = nil
+	b, err := block.MarshalProto()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message := new(primitivespb.Block)
+
+	if err := proto.Unmarshal(b, message); err != nil {
+		t.Fatal(err)
+	}
+
+	if message.GetSend().ProtoReflect().Has(message.GetSend().ProtoReflect().Descriptor().Fields().ByName("balance")) {
+		t.Error("Absent balance was encoded")
+	}
+
+	decoded, err := DecodeBlockProto(b)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decoded.Balance() != nil {
+		t.Errorf("Balance = %v, want nil", decoded.Balance())
+	}
+}
+
+func TestBlockProtoInvalidBalance(t *testing.T) {
+	for _, balance := range []string{"", "ten", "Inf", "-Inf", "0x1p-2p"} {
+		message := &primitivespb.Block{
+			Hashables: &primitivespb.Block_Send{
+				Send: &primitivespb.SendBlock{
+					Balance:     balance,
+					Destination: "TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZS",
+					Previous:    make([]byte, len(BlockHashZero)),
+				},
+			},
+		}
+
+		b, err := proto.Marshal(message)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// An empty string is the default value and is not encoded.
+		if balance == "" {
+			if block, err := DecodeBlockProto(b); (err != nil) || (block.Balance() != nil) {
+				t.Errorf("DecodeBlockProto with empty balance = %v, %v", block, err)
+			}
+
+			continue
+		}
+
+		if _, err := DecodeBlockProto(b); err == nil {
+			t.Errorf("DecodeBlockProto with balance %q succeeded", balance)
+		}
+	}
+}
+
+func TestBlockFromProtoInvalid(t *testing.T) {
+	block := NewSendBlock(NewAmount(1), mustIBAN(t, "TV5838O073KYGTWWZN0F2WZ0R8PX5ZPPZS"), BlockHash{1})
+	message, err := BlockToProto(block)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := BlockFromProto(message); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*primitivespb.Block)
+	}{
+		{"without hashables", func(m *primitivespb.Block) { m.Hashables = nil }},
+		{"with a short hash", func(m *primitivespb.Block) { m.Hash = m.Hash[1:] }},
+		{"with another hash", func(m *primitivespb.Block) { m.Hash[0] ^= 0xFF }},
+		{"with a short previous", func(m *primitivespb.Block) { m.GetSend().Previous = m.GetSend().Previous[1:] }},
+		{"with a short destination", func(m *primitivespb.Block) { m.GetSend().Destination = "TV58" }},
+		{"with a short signature", func(m *primitivespb.Block) { m.Signature = &primitivespb.Signature{Rs: []byte{1}} }},
+	}
+
+	for _, test := range tests {
+		invalid := proto.Clone(message).(*primitivespb.Block)
+		test.modify(invalid)
+
+		if _, err := BlockFromProto(invalid); err == nil {
+			t.Errorf("BlockFromProto %v succeeded", test.name)
+		}
+	}
+
+	// Messages of one type are not decoded into blocks of another.
+	b, err := block.MarshalProto()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := new(ReceiveBlock).UnmarshalProto(b); err == nil {
+		t.Error("UnmarshalProto of send into receive block succeeded")
+	}
+}
+
+func TestSignatureProtoRoundTrip(t *testing.T) {
+	hash := BlockHash{7}
+
+	for _, scheme := range []crypto.Scheme{crypto.P256, crypto.Ed25519} {
+		signer := mustSigner(t, scheme)
+		signature, err := SignHash(hash[:], signer)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded Signature
+
+		if err := decoded.UnmarshalProto(signature.MarshalProto()); err != nil {
+			t.Fatalf("UnmarshalProto %v: %v", scheme, err)
+		}
+
+		if decoded != signature {
+			t.Errorf("Signature %v = %+v, want %+v", scheme, decoded, signature)
+		}
+
+		if !decoded.Verify(hash[:], signer.Verifier()) {
+			t.Errorf("Decoded %v signature does not verify", scheme)
+		}
+
+		message := new(primitivespb.Signature)
+
+		if err := proto.Unmarshal(signature.MarshalProto(), message); err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(message.GetRs(), signature.RS[:]) || (message.GetScheme() != uint32(scheme)) {
+			t.Errorf("Generated Signature %v = %v", scheme, message)
+		}
+	}
+
+	var zero Signature
+
+	if err := zero.UnmarshalProto((&Signature{}).MarshalProto()); (err != nil) || (zero != Signature{}) {
+		t.Errorf("Zero Signature = %+v, %v", zero, err)
+	}
+}
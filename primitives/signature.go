@@ -1,24 +1,150 @@
 package primitives
 
 import (
+	"crypto/elliptic"
+	"encoding/asn1"
 	"encoding/gob"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
+
+	"github.com/kookehs/watchmen/crypto"
 )
 
-// Signature a pair of integers that represent the signature.
+// SignatureSize is the fixed length of the compact encoding of a signature.
+const SignatureSize = 64
+
+// Signature a pair of integers that represent the signature encoded as R || S.
+// Each integer occupies half of the bytes in big-endian order.
 type Signature struct {
+	RS     [SignatureSize]byte `json:"rs"`
+	Scheme crypto.Scheme       `json:"scheme"`
+}
+
+// derSignature is the ASN.1 structure of a DER encoded ECDSA signature.
+type derSignature struct {
 	R *big.Int
 	S *big.Int
 }
 
 // MakeSignature creates and initializes a Signature from the given arguments.
-func MakeSignature(r, s *big.Int) Signature {
-	return Signature{
-		R: r,
-		S: s,
+// P-256 signatures are normalized to their low S form.
+// Integers must not be negative and must fit in half of SignatureSize.
+func MakeSignature(scheme crypto.Scheme, r, s *big.Int) (Signature, error) {
+	if (r == nil) || (s == nil) || (r.Sign() < 0) || (s.Sign() < 0) {
+		return Signature{}, errors.New("Signature integers must not be nil or negative")
+	}
+
+	if scheme == crypto.P256 {
+		n := elliptic.P256().Params().N
+
+		if (r.Cmp(n) != -1) || (s.Cmp(n) != -1) {
+			return Signature{}, errors.New("Signature integers must be less than the curve order")
+		}
+
+		s = crypto.CanonicalS(s)
+	}
+
+	half := SignatureSize / 2
+
+	if (r.BitLen() > half*8) || (s.BitLen() > half*8) {
+		return Signature{}, fmt.Errorf("Signature integers must fit in %v bytes", half)
+	}
+
+	signature := Signature{
+		Scheme: scheme,
 	}
+
+	r.FillBytes(signature.RS[:half])
+	s.FillBytes(signature.RS[half:])
+	return signature, nil
+}
+
+// MakeSignatureFromBytes creates and initializes a Signature from its compact R || S encoding.
+func MakeSignatureFromBytes(scheme crypto.Scheme, b []byte) (Signature, error) {
+	if len(b) != SignatureSize {
+		return Signature{}, fmt.Errorf("Signature must be %v bytes", SignatureSize)
+	}
+
+	signature := Signature{
+		Scheme: scheme,
+	}
+
+	copy(signature.RS[:], b)
+	return signature, nil
+}
+
+// ParseDERSignature parses an ASN.1 DER encoded P-256 signature normalizing it to low S form.
+func ParseDERSignature(der []byte) (Signature, error) {
+	var parsed derSignature
+	rest, err := asn1.Unmarshal(der, &parsed)
+
+	if err != nil {
+		return Signature{}, err
+	}
+
+	if len(rest) > 0 {
+		return Signature{}, errors.New("Trailing data after DER signature")
+	}
+
+	n := elliptic.P256().Params().N
+
+	if (parsed.R.Sign() <= 0) || (parsed.S.Sign() <= 0) || (parsed.R.Cmp(n) != -1) || (parsed.S.Cmp(n) != -1) {
+		return Signature{}, errors.New("DER signature is out of range")
+	}
+
+	return MakeSignature(crypto.P256, parsed.R, parsed.S)
+}
+
+// SignHash creates a Signature of the given hash using the given private key.
+func SignHash(hash []byte, priv crypto.Signer) (Signature, error) {
+	r, s, err := priv.Sign(hash)
+
+	if err != nil {
+		return Signature{}, err
+	}
+
+	return MakeSignature(priv.Scheme(), r, s)
+}
+
+// Bytes returns the compact R || S encoding of the Signature.
+func (s *Signature) Bytes() []byte {
+	b := make([]byte, SignatureSize)
+	copy(b, s.RS[:])
+	return b
+}
+
+// DER returns the ASN.1 DER encoding of a P-256 Signature.
+func (s *Signature) DER() ([]byte, error) {
+	if s.Scheme != crypto.P256 {
+		return nil, fmt.Errorf("DER encoding is not supported for %v signatures", s.Scheme)
+	}
+
+	return asn1.Marshal(derSignature{
+		R: s.R(),
+		S: s.S(),
+	})
+}
+
+// R returns the first integer of the Signature.
+func (s *Signature) R() *big.Int {
+	return new(big.Int).SetBytes(s.RS[:SignatureSize/2])
+}
+
+// S returns the second integer of the Signature.
+func (s *Signature) S() *big.Int {
+	return new(big.Int).SetBytes(s.RS[SignatureSize/2:])
+}
+
+// Verify returns whether the Signature of the given hash was created by the owner of the given public key.
+func (s *Signature) Verify(hash []byte, pub crypto.Verifier) bool {
+	if (pub == nil) || (pub.Scheme() != s.Scheme) {
+		return false
+	}
+
+	return pub.Verify(hash, s.R(), s.S())
 }
 
 // Deserialize decodes byte data encoded by gob.
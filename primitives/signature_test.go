@@ -0,0 +1,153 @@
+package primitives
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/kookehs/watchmen/crypto"
+)
+
+func TestMakeSignatureErrors(t *testing.T) {
+	n := elliptic.P256().Params().N
+	one := big.NewInt(1)
+	large := new(big.Int).Lsh(one, SignatureSize/2*8)
+
+	tests := []struct {
+		scheme crypto.Scheme
+		r, s   *big.Int
+	}{
+		{crypto.P256, nil, one},
+		{crypto.P256, one, nil},
+		{crypto.P256, big.NewInt(-1), one},
+		{crypto.P256, one, big.NewInt(-1)},
+		{crypto.P256, n, one},
+		{crypto.P256, one, n},
+		{crypto.Ed25519, large, one},
+		{crypto.Ed25519, one, large},
+	}
+
+	for _, test := range tests {
+		if signature, err := MakeSignature(test.scheme, test.r, test.s); err == nil {
+			t.Errorf("MakeSignature(%v, %v, %v) = %+v, want error", test.scheme, test.r, test.s, signature)
+		}
+	}
+}
+
+func TestDERRoundTrip(t *testing.T) {
+	signer := mustSigner(t, crypto.P256)
+	hash := BlockHash{9}
+	signature, err := SignHash(hash[:], signer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	der, err := signature.DER()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseDERSignature(der)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed != signature {
+		t.Errorf("ParseDERSignature = %+v, want %+v", parsed, signature)
+	}
+
+	if !parsed.Verify(hash[:], signer.Verifier()) {
+		t.Error("Parsed DER signature does not verify")
+	}
+
+	ed25519, err := SignHash(hash[:], mustSigner(t, crypto.Ed25519))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := ed25519.DER(); err == nil {
+		t.Error("DER of Ed25519 signature succeeded")
+	}
+
+	n := elliptic.P256().Params().N
+	invalid := [][]byte{
+		nil,
+		{0x30, 0x00},
+		append(der, 0),
+		mustMarshalDER(t, big.NewInt(0), signature.S()),
+		mustMarshalDER(t, signature.R(), big.NewInt(0)),
+		mustMarshalDER(t, n, signature.S()),
+		mustMarshalDER(t, signature.R(), n),
+	}
+
+	for _, b := range invalid {
+		if _, err := ParseDERSignature(b); err == nil {
+			t.Errorf("ParseDERSignature(%x) succeeded", b)
+		}
+	}
+}
+
+func mustMarshalDER(t *testing.T, r, s *big.Int) []byte {
+	t.Helper()
+	der, err := asn1.Marshal(derSignature{R: r, S: s})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return der
+}
+
+func TestLowS(t *testing.T) {
+	signer := mustSigner(t, crypto.P256)
+	hash := BlockHash{5}
+	signature, err := SignHash(hash[:], signer)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !crypto.IsLowS(signature.S()) {
+		t.Fatalf("SignHash returned high S %v", signature.S())
+	}
+
+	// N - S is an equally valid ECDSA signature that must be normalized.
+	high := new(big.Int).Sub(elliptic.P256().Params().N, signature.S())
+	normalized, err := MakeSignature(crypto.P256, signature.R(), high)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if normalized != signature {
+		t.Errorf("MakeSignature with high S = %+v, want %+v", normalized, signature)
+	}
+
+	parsed, err := ParseDERSignature(mustMarshalDER(t, signature.R(), high))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed != signature {
+		t.Errorf("ParseDERSignature with high S = %+v, want %+v", parsed, signature)
+	}
+
+	// Signatures carrying a high S are rejected.
+	b := signature.Bytes()
+	high.FillBytes(b[SignatureSize/2:])
+	malleated, err := MakeSignatureFromBytes(crypto.P256, b)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if malleated.Verify(hash[:], signer.Verifier()) {
+		t.Error("Signature with high S verified")
+	}
+}
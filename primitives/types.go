@@ -2,9 +2,14 @@ package primitives
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
 	"math/big"
 )
 
+// AmountPrecision is the precision in bits of the amounts recorded in blocks.
+const AmountPrecision = 53
+
 // Amount is a type alias for big.Float that is used to represent balances for blocks.
 type Amount = *big.Float
 
@@ -13,12 +18,44 @@ func NewAmount(amt float64) Amount {
 	return big.NewFloat(amt)
 }
 
+// NormalizeAmount returns a copy of the given Amount rounded to AmountPrecision and marked as exact.
+// Blocks only record normalized amounts so that amounts parsed from text hash the same as the originals.
+func NormalizeAmount(amt Amount) Amount {
+	if amt == nil {
+		return nil
+	}
+
+	return new(big.Float).SetPrec(AmountPrecision).Set(amt)
+}
+
 // BlockHash is a sha256 hash of a block.
 type BlockHash [sha256.Size]byte
 
 // BlockHashZero is the zero value of a BlockHash
 var BlockHashZero = [sha256.Size]byte{}
 
+// ParseBlockHash returns the BlockHash represented by the given hex string.
+func ParseBlockHash(s string) (BlockHash, error) {
+	var hash BlockHash
+	decoded, err := hex.DecodeString(s)
+
+	if err != nil {
+		return hash, err
+	}
+
+	if len(decoded) != len(hash) {
+		return hash, fmt.Errorf("Block hash must be %v bytes", len(hash))
+	}
+
+	copy(hash[:], decoded)
+	return hash, nil
+}
+
+// String returns the hex representation of the BlockHash.
+func (bh BlockHash) String() string {
+	return hex.EncodeToString(bh[:])
+}
+
 // BlockType is used to represent different block types in the smallest primitive possible.
 type BlockType uint8
 
@@ -29,4 +66,49 @@ const (
 	Open
 	Receive
 	Send
+	Name
+	Rotate
+)
+
+// ParseBlockType returns the BlockType with the given name.
+func ParseBlockType(name string) (BlockType, error) {
+	for bt := Change; bt <= Rotate; bt++ {
+		if bt.String() == name {
+			return bt, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown block type %q", name)
+}
+
+// String returns the name of the BlockType.
+func (bt BlockType) String() string {
+	switch bt {
+	case Change:
+		return "Change"
+	case Delegate:
+		return "Delegate"
+	case Open:
+		return "Open"
+	case Receive:
+		return "Receive"
+	case Send:
+		return "Send"
+	case Name:
+		return "Name"
+	case Rotate:
+		return "Rotate"
+	default:
+		return fmt.Sprintf("BlockType(%d)", uint8(bt))
+	}
+}
+
+// NameAction is used to represent the operation a NameBlock performs on a username.
+type NameAction uint8
+
+// Various name actions
+const (
+	RegisterName NameAction = iota
+	ReleaseName
+	TransferName
 )
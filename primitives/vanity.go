@@ -0,0 +1,145 @@
+package primitives
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/kookehs/watchmen/crypto"
+	"github.com/kookehs/watchmen/encoding/base36"
+)
+
+// KeySearch describes the Key to look for when generating keys in parallel.
+// Every Key found has an Address that can be represented with the direct ICAP encoding.
+type KeySearch struct {
+	// Prefix the BBAN of the Address must start with, ignoring case
+	Prefix string
+	// Scheme of the generated keys
+	Scheme crypto.Scheme
+	// Number of goroutines to search with, defaulting to the number of CPUs
+	Workers int
+}
+
+// NewKeySearch creates and initializes a KeySearch for the given scheme and prefix.
+func NewKeySearch(scheme crypto.Scheme, prefix string) *KeySearch {
+	return &KeySearch{
+		Prefix: strings.ToUpper(prefix),
+		Scheme: scheme,
+	}
+}
+
+// Match returns whether the given Address satisfies the KeySearch.
+func (ks *KeySearch) Match(a Address) bool {
+	if !a.ICAPCompatible() {
+		return false
+	}
+
+	bban, err := BBANFromAddress(a)
+
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(bban.String(), strings.ToUpper(ks.Prefix))
+}
+
+// Validate returns whether an Address can satisfy the KeySearch.
+func (ks *KeySearch) Validate() error {
+	prefix := strings.ToUpper(ks.Prefix)
+
+	if len(prefix) > ICAPDirectSize {
+		return fmt.Errorf("Prefix must not exceed %v characters", ICAPDirectSize)
+	}
+
+	for i, c := range prefix {
+		if !strings.ContainsRune(base36.Alphabet, c) {
+			return base36.CorruptInputError(i)
+		}
+	}
+
+	return nil
+}
+
+// Generate searches for a matching Key across multiple goroutines until one is found or the context is done.
+// The expected number of attempts grows by a factor of 36 for every character in Prefix.
+func (ks *KeySearch) Generate(parent context.Context) (*Key, error) {
+	if err := ks.Validate(); err != nil {
+		return nil, err
+	}
+
+	workers := ks.Workers
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	found := make(chan crypto.Signer, 1)
+	failed := make(chan error, 1)
+	var group sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		group.Add(1)
+
+		go func() {
+			defer group.Done()
+			priv, err := ks.search(ctx)
+
+			if err != nil {
+				select {
+				case failed <- err:
+					cancel()
+				default:
+				}
+
+				return
+			}
+
+			select {
+			case found <- priv:
+				cancel()
+			default:
+			}
+		}()
+	}
+
+	group.Wait()
+
+	select {
+	case priv := <-found:
+		return NewKeyFromSigner(priv)
+	default:
+	}
+
+	if parent.Err() != nil {
+		return nil, parent.Err()
+	}
+
+	return nil, <-failed
+}
+
+// search generates keys until one matches or the context is done.
+func (ks *KeySearch) search(ctx context.Context) (crypto.Signer, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		priv, err := crypto.GenerateSigner(ks.Scheme, rand.Reader)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if ks.Match(AddressFromPublicKey(priv.Verifier())) {
+			return priv, nil
+		}
+	}
+}
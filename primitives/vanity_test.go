@@ -0,0 +1,78 @@
+package primitives
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kookehs/watchmen/crypto"
+)
+
+func TestKeySearchPrefix(t *testing.T) {
+	search := NewKeySearch(crypto.Ed25519, "k")
+	search.Workers = 2
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	key, err := search.Generate(ctx)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key.Scheme != crypto.Ed25519 {
+		t.Errorf("Scheme = %v, want %v", key.Scheme, crypto.Ed25519)
+	}
+
+	if !key.Address.ICAPCompatible() {
+		t.Fatalf("Address %v is not ICAP compatible", key.Address)
+	}
+
+	bban, err := BBANFromAddress(key.Address)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(bban.String(), "K") {
+		t.Errorf("BBAN %v does not start with K", bban.String())
+	}
+
+	if key.Address != AddressFromPublicKey(key.Verifier()) {
+		t.Error("Address is not derived from the public key")
+	}
+}
+
+func TestKeySearchCancel(t *testing.T) {
+	search := NewKeySearch(crypto.Ed25519, "")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if key, err := search.Generate(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Generate with cancelled context = %v, %v, want %v", key, err, context.Canceled)
+	}
+
+	// A prefix this long is never found before the deadline.
+	search = NewKeySearch(crypto.Ed25519, "ZZZZZZZZZZ")
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+
+	if key, err := search.Generate(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Generate past deadline = %v, %v, want %v", key, err, context.DeadlineExceeded)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Generate returned %v after the deadline", elapsed)
+	}
+}
+
+func TestKeySearchValidate(t *testing.T) {
+	for _, prefix := range []string{"A-", "ä", strings.Repeat("A", ICAPDirectSize+1)} {
+		if _, err := NewKeySearch(crypto.P256, prefix).Generate(context.Background()); err == nil {
+			t.Errorf("Generate with prefix %q succeeded", prefix)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package primitives
+
+import (
+	"errors"
+
+	"github.com/kookehs/watchmen/crypto"
+	"github.com/kookehs/watchmen/crypto/hd"
+)
+
+// Wallet derives Keys hierarchically from a single mnemonic seed.
+// Backing up the mnemonic is enough to recover every Key derived from the Wallet.
+type Wallet struct {
+	Master *hd.ExtendedKey
+}
+
+// NewWallet creates and initializes a Wallet of the given scheme from the given mnemonic and passphrase.
+func NewWallet(scheme crypto.Scheme, mnemonic, passphrase string) (*Wallet, error) {
+	seed, err := hd.MnemonicToSeed(mnemonic, passphrase)
+
+	if err != nil {
+		return nil, err
+	}
+
+	master, err := hd.NewMasterKey(scheme, seed)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Wallet{
+		Master: master,
+	}, nil
+}
+
+// Key returns the Key for the given account and index following hd.AccountPath.
+func (w *Wallet) Key(account, index uint32) (*Key, error) {
+	return w.KeyAtPath(hd.AccountPath(account, index))
+}
+
+// KeyAtPath returns the Key at the given path from the master key.
+func (w *Wallet) KeyAtPath(path []uint32) (*Key, error) {
+	extended, err := w.Master.DerivePath(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := extended.Signer()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeyFromSigner(signer)
+}
+
+// NextKeyForICAP returns the first Key of the given account starting at index whose Address
+// can be represented with the direct ICAP encoding along with the index it was found at.
+func (w *Wallet) NextKeyForICAP(account, index uint32) (*Key, uint32, error) {
+	for ; index < hd.HardenedOffset; index++ {
+		key, err := w.Key(account, index)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if key.Address.ICAPCompatible() {
+			return key, index, nil
+		}
+	}
+
+	return nil, 0, errors.New("No ICAP compatible key left in account")
+}
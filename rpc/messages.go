@@ -0,0 +1,114 @@
+package rpc
+
+import (
+	"sort"
+
+	"github.com/kookehs/watchmen/core"
+	"github.com/kookehs/watchmen/primitives"
+	"github.com/kookehs/watchmen/primitives/primitivespb"
+)
+
+// The messages of node.proto are generated in node.pb.go.
+// Primitives are converted to the messages generated from primitives.proto by their Proto methods.
+
+// NewAccount returns the Account message of the given Account.
+func NewAccount(ledger *core.Ledger, account *core.Account) (*Account, error) {
+	message := &Account{
+		Bban:     account.BBAN.String(),
+		Blocks:   uint64(len(ledger.Blocks[account.IBAN.String()])),
+		Delegate: account.Delegate,
+		Forged:   account.Forged,
+		Iban:     account.IBAN.String(),
+		Missed:   account.Missed,
+		Share:    account.Share,
+		Username: ledger.Username(account.IBAN),
+	}
+
+	if latest := ledger.LatestBlock(account.IBAN); latest != nil {
+		message.Balance = latest.Balance().Text('g', -1)
+	}
+
+	for delegate := range account.Delegates {
+		message.Delegates = append(message.Delegates, delegate)
+	}
+
+	sort.Strings(message.Delegates)
+
+	// Accounts may be imported without private keys so the public key is read from the chain.
+	if account.Multisig() {
+		message.Policy = account.Policy.Proto()
+	} else if authorized, err := ledger.AuthorizedKey(account.IBAN); err == nil {
+		key := primitives.MakePublicKey(authorized)
+		message.Key = key.Proto()
+	}
+
+	return message, nil
+}
+
+// NewBlock returns the Block message of the given block.
+func NewBlock(block primitives.Block) (*primitivespb.Block, error) {
+	return primitives.BlockToProto(block)
+}
+
+// NewBlockEvent returns the BlockEvent message of the given BlockAppended event.
+func NewBlockEvent(event core.Event) (*BlockEvent, error) {
+	block, err := NewBlock(event.Block)
+
+	if err != nil {
+		return nil, err
+	}
+
+	message := &BlockEvent{
+		Block: block,
+		Iban:  event.IBAN,
+		Round: event.Round,
+	}
+
+	if event.Amount != nil {
+		message.Amount = event.Amount.Text('g', -1)
+	}
+
+	return message, nil
+}
+
+// NewDelegateList returns the DelegateList message of the given delegates.
+func NewDelegateList(ledger *core.Ledger, delegates core.Delegates) *DelegateList {
+	message := &DelegateList{
+		Delegates: make([]*Delegate, 0, len(delegates)),
+	}
+
+	for _, delegate := range delegates {
+		message.Delegates = append(message.Delegates, newDelegate(ledger, delegate))
+	}
+
+	return message
+}
+
+// NewRound returns the Round message of the current Round of the given DPoS.
+func NewRound(ledger *core.Ledger, dpos *core.DPoS) *Round {
+	message := &Round{
+		Number: dpos.Rounds,
+	}
+
+	if dpos.Round != nil {
+		message.Index = uint64(dpos.Round.Index)
+
+		for _, forger := range dpos.Round.Forgers {
+			message.Forgers = append(message.Forgers, newDelegate(ledger, forger))
+		}
+	}
+
+	return message
+}
+
+// newDelegate returns the Delegate message of the given Delegate.
+func newDelegate(ledger *core.Ledger, delegate *core.Delegate) *Delegate {
+	return &Delegate{
+		Forged:   delegate.Account.Forged,
+		Iban:     delegate.Account.IBAN.String(),
+		Missed:   delegate.Account.Missed,
+		Share:    delegate.Account.Share,
+		Username: ledger.Username(delegate.Account.IBAN),
+		Weight:   delegate.Weight.Text('g', -1),
+	}
+}
@@ -0,0 +1,750 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: rpc/node.proto
+
+package rpc
+
+import (
+	primitivespb "github.com/kookehs/watchmen/primitives/primitivespb"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AccountRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// IBAN or username of the account
+	Id            string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountRequest) Reset() {
+	*x = AccountRequest{}
+	mi := &file_rpc_node_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountRequest) ProtoMessage() {}
+
+func (x *AccountRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountRequest.ProtoReflect.Descriptor instead.
+func (*AccountRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *AccountRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type BlockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hash          []byte                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockRequest) Reset() {
+	*x = BlockRequest{}
+	mi := &file_rpc_node_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockRequest) ProtoMessage() {}
+
+func (x *BlockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockRequest.ProtoReflect.Descriptor instead.
+func (*BlockRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BlockRequest) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+type DelegatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelegatesRequest) Reset() {
+	*x = DelegatesRequest{}
+	mi := &file_rpc_node_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelegatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegatesRequest) ProtoMessage() {}
+
+func (x *DelegatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegatesRequest.ProtoReflect.Descriptor instead.
+func (*DelegatesRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{2}
+}
+
+type RoundRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoundRequest) Reset() {
+	*x = RoundRequest{}
+	mi := &file_rpc_node_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoundRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoundRequest) ProtoMessage() {}
+
+func (x *RoundRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoundRequest.ProtoReflect.Descriptor instead.
+func (*RoundRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{3}
+}
+
+type SubscribeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only stream blocks appended to these accounts if any are given
+	Ibans         []string `protobuf:"bytes,1,rep,name=ibans,proto3" json:"ibans,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_rpc_node_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *SubscribeRequest) GetIbans() []string {
+	if x != nil {
+		return x.Ibans
+	}
+	return nil
+}
+
+type BlockEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Account the block was appended to
+	Iban  string              `protobuf:"bytes,1,opt,name=iban,proto3" json:"iban,omitempty"`
+	Block *primitivespb.Block `protobuf:"bytes,2,opt,name=block,proto3" json:"block,omitempty"`
+	// Amount sent or received by the block
+	Amount        string `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Round         uint64 `protobuf:"varint,4,opt,name=round,proto3" json:"round,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BlockEvent) Reset() {
+	*x = BlockEvent{}
+	mi := &file_rpc_node_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BlockEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlockEvent) ProtoMessage() {}
+
+func (x *BlockEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlockEvent.ProtoReflect.Descriptor instead.
+func (*BlockEvent) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *BlockEvent) GetIban() string {
+	if x != nil {
+		return x.Iban
+	}
+	return ""
+}
+
+func (x *BlockEvent) GetBlock() *primitivespb.Block {
+	if x != nil {
+		return x.Block
+	}
+	return nil
+}
+
+func (x *BlockEvent) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *BlockEvent) GetRound() uint64 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+type Account struct {
+	state         protoimpl.MessageState  `protogen:"open.v1"`
+	Iban          string                  `protobuf:"bytes,1,opt,name=iban,proto3" json:"iban,omitempty"`
+	Bban          string                  `protobuf:"bytes,2,opt,name=bban,proto3" json:"bban,omitempty"`
+	Username      string                  `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	Balance       string                  `protobuf:"bytes,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	Blocks        uint64                  `protobuf:"varint,5,opt,name=blocks,proto3" json:"blocks,omitempty"`
+	Delegate      bool                    `protobuf:"varint,6,opt,name=delegate,proto3" json:"delegate,omitempty"`
+	Delegates     []string                `protobuf:"bytes,7,rep,name=delegates,proto3" json:"delegates,omitempty"`
+	Forged        uint64                  `protobuf:"varint,8,opt,name=forged,proto3" json:"forged,omitempty"`
+	Missed        uint64                  `protobuf:"varint,9,opt,name=missed,proto3" json:"missed,omitempty"`
+	Share         float64                 `protobuf:"fixed64,10,opt,name=share,proto3" json:"share,omitempty"`
+	Key           *primitivespb.PublicKey `protobuf:"bytes,11,opt,name=key,proto3" json:"key,omitempty"`
+	Policy        *primitivespb.Policy    `protobuf:"bytes,12,opt,name=policy,proto3" json:"policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_rpc_node_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Account) GetIban() string {
+	if x != nil {
+		return x.Iban
+	}
+	return ""
+}
+
+func (x *Account) GetBban() string {
+	if x != nil {
+		return x.Bban
+	}
+	return ""
+}
+
+func (x *Account) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Account) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+func (x *Account) GetBlocks() uint64 {
+	if x != nil {
+		return x.Blocks
+	}
+	return 0
+}
+
+func (x *Account) GetDelegate() bool {
+	if x != nil {
+		return x.Delegate
+	}
+	return false
+}
+
+func (x *Account) GetDelegates() []string {
+	if x != nil {
+		return x.Delegates
+	}
+	return nil
+}
+
+func (x *Account) GetForged() uint64 {
+	if x != nil {
+		return x.Forged
+	}
+	return 0
+}
+
+func (x *Account) GetMissed() uint64 {
+	if x != nil {
+		return x.Missed
+	}
+	return 0
+}
+
+func (x *Account) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+func (x *Account) GetKey() *primitivespb.PublicKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *Account) GetPolicy() *primitivespb.Policy {
+	if x != nil {
+		return x.Policy
+	}
+	return nil
+}
+
+type Delegate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Iban          string                 `protobuf:"bytes,1,opt,name=iban,proto3" json:"iban,omitempty"`
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Weight        string                 `protobuf:"bytes,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Forged        uint64                 `protobuf:"varint,4,opt,name=forged,proto3" json:"forged,omitempty"`
+	Missed        uint64                 `protobuf:"varint,5,opt,name=missed,proto3" json:"missed,omitempty"`
+	Share         float64                `protobuf:"fixed64,6,opt,name=share,proto3" json:"share,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Delegate) Reset() {
+	*x = Delegate{}
+	mi := &file_rpc_node_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Delegate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Delegate) ProtoMessage() {}
+
+func (x *Delegate) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Delegate.ProtoReflect.Descriptor instead.
+func (*Delegate) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Delegate) GetIban() string {
+	if x != nil {
+		return x.Iban
+	}
+	return ""
+}
+
+func (x *Delegate) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *Delegate) GetWeight() string {
+	if x != nil {
+		return x.Weight
+	}
+	return ""
+}
+
+func (x *Delegate) GetForged() uint64 {
+	if x != nil {
+		return x.Forged
+	}
+	return 0
+}
+
+func (x *Delegate) GetMissed() uint64 {
+	if x != nil {
+		return x.Missed
+	}
+	return 0
+}
+
+func (x *Delegate) GetShare() float64 {
+	if x != nil {
+		return x.Share
+	}
+	return 0
+}
+
+type DelegateList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Delegates     []*Delegate            `protobuf:"bytes,1,rep,name=delegates,proto3" json:"delegates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DelegateList) Reset() {
+	*x = DelegateList{}
+	mi := &file_rpc_node_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DelegateList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DelegateList) ProtoMessage() {}
+
+func (x *DelegateList) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DelegateList.ProtoReflect.Descriptor instead.
+func (*DelegateList) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DelegateList) GetDelegates() []*Delegate {
+	if x != nil {
+		return x.Delegates
+	}
+	return nil
+}
+
+type Round struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of rounds that have been started
+	Number uint64 `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	// Position of the current forger
+	Index         uint64      `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Forgers       []*Delegate `protobuf:"bytes,3,rep,name=forgers,proto3" json:"forgers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Round) Reset() {
+	*x = Round{}
+	mi := &file_rpc_node_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Round) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Round) ProtoMessage() {}
+
+func (x *Round) ProtoReflect() protoreflect.Message {
+	mi := &file_rpc_node_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Round.ProtoReflect.Descriptor instead.
+func (*Round) Descriptor() ([]byte, []int) {
+	return file_rpc_node_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Round) GetNumber() uint64 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+func (x *Round) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *Round) GetForgers() []*Delegate {
+	if x != nil {
+		return x.Forgers
+	}
+	return nil
+}
+
+var File_rpc_node_proto protoreflect.FileDescriptor
+
+const file_rpc_node_proto_rawDesc = "" +
+	"\n" +
+	"\x0erpc/node.proto\x12\bwatchmen\x1a\x1bprimitives/primitives.proto\" \n" +
+	"\x0eAccountRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\"\n" +
+	"\fBlockRequest\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\fR\x04hash\"\x12\n" +
+	"\x10DelegatesRequest\"\x0e\n" +
+	"\fRoundRequest\"(\n" +
+	"\x10SubscribeRequest\x12\x14\n" +
+	"\x05ibans\x18\x01 \x03(\tR\x05ibans\"\x80\x01\n" +
+	"\n" +
+	"BlockEvent\x12\x12\n" +
+	"\x04iban\x18\x01 \x01(\tR\x04iban\x120\n" +
+	"\x05block\x18\x02 \x01(\v2\x1a.watchmen.primitives.BlockR\x05block\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x14\n" +
+	"\x05round\x18\x04 \x01(\x04R\x05round\"\xe6\x02\n" +
+	"\aAccount\x12\x12\n" +
+	"\x04iban\x18\x01 \x01(\tR\x04iban\x12\x12\n" +
+	"\x04bban\x18\x02 \x01(\tR\x04bban\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12\x18\n" +
+	"\abalance\x18\x04 \x01(\tR\abalance\x12\x16\n" +
+	"\x06blocks\x18\x05 \x01(\x04R\x06blocks\x12\x1a\n" +
+	"\bdelegate\x18\x06 \x01(\bR\bdelegate\x12\x1c\n" +
+	"\tdelegates\x18\a \x03(\tR\tdelegates\x12\x16\n" +
+	"\x06forged\x18\b \x01(\x04R\x06forged\x12\x16\n" +
+	"\x06missed\x18\t \x01(\x04R\x06missed\x12\x14\n" +
+	"\x05share\x18\n" +
+	" \x01(\x01R\x05share\x120\n" +
+	"\x03key\x18\v \x01(\v2\x1e.watchmen.primitives.PublicKeyR\x03key\x123\n" +
+	"\x06policy\x18\f \x01(\v2\x1b.watchmen.primitives.PolicyR\x06policy\"\x98\x01\n" +
+	"\bDelegate\x12\x12\n" +
+	"\x04iban\x18\x01 \x01(\tR\x04iban\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\tR\x06weight\x12\x16\n" +
+	"\x06forged\x18\x04 \x01(\x04R\x06forged\x12\x16\n" +
+	"\x06missed\x18\x05 \x01(\x04R\x06missed\x12\x14\n" +
+	"\x05share\x18\x06 \x01(\x01R\x05share\"@\n" +
+	"\fDelegateList\x120\n" +
+	"\tdelegates\x18\x01 \x03(\v2\x12.watchmen.DelegateR\tdelegates\"c\n" +
+	"\x05Round\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\x04R\x06number\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x04R\x05index\x12,\n" +
+	"\aforgers\x18\x03 \x03(\v2\x12.watchmen.DelegateR\aforgers2\xc1\x02\n" +
+	"\x04Node\x129\n" +
+	"\n" +
+	"GetAccount\x12\x18.watchmen.AccountRequest\x1a\x11.watchmen.Account\x12>\n" +
+	"\bGetBlock\x12\x16.watchmen.BlockRequest\x1a\x1a.watchmen.primitives.Block\x12B\n" +
+	"\fGetDelegates\x12\x1a.watchmen.DelegatesRequest\x1a\x16.watchmen.DelegateList\x123\n" +
+	"\bGetRound\x12\x16.watchmen.RoundRequest\x1a\x0f.watchmen.Round\x12E\n" +
+	"\x0fSubscribeBlocks\x12\x1a.watchmen.SubscribeRequest\x1a\x14.watchmen.BlockEvent0\x01B!Z\x1fgithub.com/kookehs/watchmen/rpcb\x06proto3"
+
+var (
+	file_rpc_node_proto_rawDescOnce sync.Once
+	file_rpc_node_proto_rawDescData []byte
+)
+
+func file_rpc_node_proto_rawDescGZIP() []byte {
+	file_rpc_node_proto_rawDescOnce.Do(func() {
+		file_rpc_node_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_rpc_node_proto_rawDesc), len(file_rpc_node_proto_rawDesc)))
+	})
+	return file_rpc_node_proto_rawDescData
+}
+
+var file_rpc_node_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_rpc_node_proto_goTypes = []any{
+	(*AccountRequest)(nil),         // 0: watchmen.AccountRequest
+	(*BlockRequest)(nil),           // 1: watchmen.BlockRequest
+	(*DelegatesRequest)(nil),       // 2: watchmen.DelegatesRequest
+	(*RoundRequest)(nil),           // 3: watchmen.RoundRequest
+	(*SubscribeRequest)(nil),       // 4: watchmen.SubscribeRequest
+	(*BlockEvent)(nil),             // 5: watchmen.BlockEvent
+	(*Account)(nil),                // 6: watchmen.Account
+	(*Delegate)(nil),               // 7: watchmen.Delegate
+	(*DelegateList)(nil),           // 8: watchmen.DelegateList
+	(*Round)(nil),                  // 9: watchmen.Round
+	(*primitivespb.Block)(nil),     // 10: watchmen.primitives.Block
+	(*primitivespb.PublicKey)(nil), // 11: watchmen.primitives.PublicKey
+	(*primitivespb.Policy)(nil),    // 12: watchmen.primitives.Policy
+}
+var file_rpc_node_proto_depIdxs = []int32{
+	10, // 0: watchmen.BlockEvent.block:type_name -> watchmen.primitives.Block
+	11, // 1: watchmen.Account.key:type_name -> watchmen.primitives.PublicKey
+	12, // 2: watchmen.Account.policy:type_name -> watchmen.primitives.Policy
+	7,  // 3: watchmen.DelegateList.delegates:type_name -> watchmen.Delegate
+	7,  // 4: watchmen.Round.forgers:type_name -> watchmen.Delegate
+	0,  // 5: watchmen.Node.GetAccount:input_type -> watchmen.AccountRequest
+	1,  // 6: watchmen.Node.GetBlock:input_type -> watchmen.BlockRequest
+	2,  // 7: watchmen.Node.GetDelegates:input_type -> watchmen.DelegatesRequest
+	3,  // 8: watchmen.Node.GetRound:input_type -> watchmen.RoundRequest
+	4,  // 9: watchmen.Node.SubscribeBlocks:input_type -> watchmen.SubscribeRequest
+	6,  // 10: watchmen.Node.GetAccount:output_type -> watchmen.Account
+	10, // 11: watchmen.Node.GetBlock:output_type -> watchmen.primitives.Block
+	8,  // 12: watchmen.Node.GetDelegates:output_type -> watchmen.DelegateList
+	9,  // 13: watchmen.Node.GetRound:output_type -> watchmen.Round
+	5,  // 14: watchmen.Node.SubscribeBlocks:output_type -> watchmen.BlockEvent
+	10, // [10:15] is the sub-list for method output_type
+	5,  // [5:10] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_rpc_node_proto_init() }
+func file_rpc_node_proto_init() {
+	if File_rpc_node_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_rpc_node_proto_rawDesc), len(file_rpc_node_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_rpc_node_proto_goTypes,
+		DependencyIndexes: file_rpc_node_proto_depIdxs,
+		MessageInfos:      file_rpc_node_proto_msgTypes,
+	}.Build()
+	File_rpc_node_proto = out.File
+	file_rpc_node_proto_goTypes = nil
+	file_rpc_node_proto_depIdxs = nil
+}
@@ -0,0 +1,186 @@
+package rpc
+
+//go:generate protoc -I .. --go_out=.. --go_opt=module=github.com/kookehs/watchmen --go-grpc_out=.. --go-grpc_opt=module=github.com/kookehs/watchmen primitives/primitives.proto rpc/node.proto
+
+import (
+	"context"
+	"net"
+	"strings"
+
+	"github.com/kookehs/watchmen/core"
+	"github.com/kookehs/watchmen/primitives"
+	"github.com/kookehs/watchmen/primitives/primitivespb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Defines limits of the server
+var (
+	// Maximum size of a request message in bytes
+	MaxMessageSize int = 4 << 20
+	// Number of events buffered for each block subscription
+	StreamBufferSize int = 256
+)
+
+// Server implements the Node service of node.proto.
+// State of the Node is read while holding its read lock.
+type Server struct {
+	UnimplementedNodeServer
+	Node *core.Node
+}
+
+// NewServer creates and initializes a Server for the given Node.
+func NewServer(node *core.Node) *Server {
+	return &Server{
+		Node: node,
+	}
+}
+
+// ListenAndServeTLS serves gRPC on the given address using the given certificate and key files.
+func (s *Server) ListenAndServeTLS(addr, certFile, keyFile string) error {
+	creds, err := credentials.NewServerTLSFromFile(certFile, keyFile)
+
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", addr)
+
+	if err != nil {
+		return err
+	}
+
+	server := grpc.NewServer(grpc.Creds(creds), grpc.MaxRecvMsgSize(MaxMessageSize))
+	s.Register(server)
+	return server.Serve(listener)
+}
+
+// Register registers the Node service with the given gRPC server.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	RegisterNodeServer(registrar, s)
+}
+
+// GetAccount returns the Account identified by the IBAN or username of the request.
+func (s *Server) GetAccount(ctx context.Context, request *AccountRequest) (*Account, error) {
+	s.Node.RLock()
+	defer s.Node.RUnlock()
+
+	ledger := s.Node.Ledger
+	id := strings.TrimSpace(request.GetId())
+	var account *core.Account
+
+	if iban, err := primitives.ParseIBAN(id); err == nil {
+		account = ledger.Accounts[iban.String()]
+	}
+
+	if iban, exist := ledger.Users.IBAN(strings.ToLower(id)); (account == nil) && exist {
+		account = ledger.Accounts[iban.String()]
+	}
+
+	if account == nil {
+		return nil, status.Errorf(codes.NotFound, "Account %q does not exist", id)
+	}
+
+	message, err := NewAccount(ledger, account)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
+}
+
+// GetBlock returns the block with the hash of the request.
+func (s *Server) GetBlock(ctx context.Context, request *BlockRequest) (*primitivespb.Block, error) {
+	var hash primitives.BlockHash
+
+	if len(request.GetHash()) != len(hash) {
+		return nil, status.Errorf(codes.InvalidArgument, "Block hash must be %v bytes", len(hash))
+	}
+
+	copy(hash[:], request.GetHash())
+	s.Node.RLock()
+	block, err := s.Node.Ledger.BlockByHash(hash)
+	s.Node.RUnlock()
+
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	message, err := NewBlock(block)
+
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return message, nil
+}
+
+// GetDelegates returns every delegate ranked by weight.
+func (s *Server) GetDelegates(ctx context.Context, request *DelegatesRequest) (*DelegateList, error) {
+	s.Node.RLock()
+	defer s.Node.RUnlock()
+	return NewDelegateList(s.Node.Ledger, s.Node.DPoS.Delegates), nil
+}
+
+// GetRound returns the current round and its forgers.
+func (s *Server) GetRound(ctx context.Context, request *RoundRequest) (*Round, error) {
+	s.Node.RLock()
+	defer s.Node.RUnlock()
+	return NewRound(s.Node.Ledger, s.Node.DPoS), nil
+}
+
+// SubscribeBlocks streams appended blocks matching the request until the client goes away.
+// Headers are sent once the subscription is active so that clients know no blocks are missed after they arrive.
+func (s *Server) SubscribeBlocks(request *SubscribeRequest, stream Node_SubscribeBlocksServer) error {
+	ibans := make(map[string]bool)
+
+	for _, id := range request.GetIbans() {
+		iban, err := primitives.ParseIBAN(id)
+
+		if err != nil {
+			return status.Error(codes.InvalidArgument, err.Error())
+		}
+
+		ibans[iban.String()] = true
+	}
+
+	if s.Node.Events == nil {
+		return status.Error(codes.Unavailable, "Events are not available")
+	}
+
+	subscription := s.Node.Events.Subscribe(StreamBufferSize, core.BlockAppended)
+	defer subscription.Close()
+
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case event, ok := <-subscription.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "Subscription closed")
+			}
+
+			if (len(ibans) > 0) && !ibans[event.IBAN] {
+				continue
+			}
+
+			message, err := NewBlockEvent(event)
+
+			if err != nil {
+				return status.Error(codes.Internal, err.Error())
+			}
+
+			if err := stream.Send(message); err != nil {
+				return err
+			}
+		case <-stream.Context().Done():
+			return status.FromContextError(stream.Context().Err()).Err()
+		}
+	}
+}
@@ -0,0 +1,344 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kookehs/watchmen/core"
+	"github.com/kookehs/watchmen/primitives"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+)
+
+type testStatus struct{}
+
+func (testStatus) Available(string) bool {
+	return true
+}
+
+// newTestNode returns a Node with genesis delegates and a funded account named merchant.
+func newTestNode(t *testing.T) (*core.Node, *core.Account, *core.Account) {
+	t.Helper()
+	ledger := core.NewLedger()
+	dpos := core.NewDPoS()
+	node := core.NewNode(dpos, ledger, testStatus{})
+	genesis, err := node.OpenGenesisAccount("genesis")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node.OpenGenesisDelegates(genesis)
+	account, err := node.OpenAccount("merchant")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pay(t, node, 10, account, genesis)
+	return node, genesis, account
+}
+
+// newTestClient serves the given Node over an in-memory connection and returns a client for it.
+func newTestClient(t *testing.T, node *core.Node) NodeClient {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	NewServer(node).Register(server)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	return NewNodeClient(conn)
+}
+
+// pay sends the given amount from src to dst.
+func pay(t *testing.T, node *core.Node, amt float64, dst, src *core.Account) primitives.Block {
+	t.Helper()
+	block, err := node.Transfer(primitives.NewAmount(amt), dst.IBAN, src.IBAN)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return block
+}
+
+// checkAmount reports whether the given decimal text does not parse to exactly want.
+func checkAmount(t *testing.T, name, text string, want primitives.Amount) {
+	t.Helper()
+	got, _, err := big.ParseFloat(text, 10, primitives.AmountPrecision, big.ToNearestEven)
+
+	if (err != nil) || (got.Cmp(want) != 0) {
+		t.Errorf("%v = %v, want %v", name, text, want.Text('g', -1))
+	}
+}
+
+// decodeBlock decodes a Block message with DecodeBlockProto.
+func decodeBlock(t *testing.T, message proto.Message) primitives.Block {
+	t.Helper()
+	b, err := proto.Marshal(message)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, err := primitives.DecodeBlockProto(b)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return block
+}
+
+func TestGetAccount(t *testing.T) {
+	node, genesis, account := newTestNode(t)
+	client := newTestClient(t, node)
+	ledger := node.Ledger
+
+	// The balance has more significant digits than String keeps so it must not be rounded.
+	pay(t, node, 0.123456789012, account, genesis)
+
+	for _, id := range []string{account.IBAN.String(), account.IBAN.Printable(), "Merchant"} {
+		message, err := client.GetAccount(context.Background(), &AccountRequest{Id: id})
+
+		if err != nil {
+			t.Fatalf("GetAccount(%q): %v", id, err)
+		}
+
+		if (message.GetIban() != account.IBAN.String()) || (message.GetBban() != account.BBAN.String()) || (message.GetUsername() != "merchant") {
+			t.Errorf("GetAccount(%q) = %v", id, message)
+		}
+
+		checkAmount(t, "Balance", message.GetBalance(), ledger.LatestBlock(account.IBAN).Balance())
+
+		if blocks := uint64(len(ledger.Blocks[account.IBAN.String()])); message.GetBlocks() != blocks {
+			t.Errorf("Blocks = %v, want %v", message.GetBlocks(), blocks)
+		}
+
+		key := primitives.MakePublicKey(account.Key.Verifier())
+
+		if b, err := proto.Marshal(message.GetKey()); (err != nil) || !bytes.Equal(b, key.MarshalProto()) {
+			t.Errorf("Key = %v, want %x", message.GetKey(), key.MarshalProto())
+		}
+	}
+
+	_, err := client.GetAccount(context.Background(), &AccountRequest{Id: "nobody"})
+
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetAccount(nobody) = %v, want %v", err, codes.NotFound)
+	}
+}
+
+func TestGetBlock(t *testing.T) {
+	node, genesis, account := newTestNode(t)
+	client := newTestClient(t, node)
+	block := pay(t, node, 1, account, genesis)
+	hash, err := block.Hash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := client.GetBlock(context.Background(), &BlockRequest{Hash: hash[:]})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.GetSend() == nil {
+		t.Fatalf("GetBlock = %v, want SendBlock", message)
+	}
+
+	if message.GetSend().GetDestination() != account.IBAN.String() {
+		t.Errorf("Destination = %v, want %v", message.GetSend().GetDestination(), account.IBAN.String())
+	}
+
+	decoded, err := decodeBlock(t, message).Hash()
+
+	if (err != nil) || (decoded != hash) {
+		t.Errorf("Hash of decoded block = %v, %v, want %v", decoded, err, hash)
+	}
+
+	if _, err := client.GetBlock(context.Background(), &BlockRequest{Hash: hash[:8]}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("GetBlock(short hash) = %v, want %v", err, codes.InvalidArgument)
+	}
+
+	var unknown primitives.BlockHash
+
+	if _, err := client.GetBlock(context.Background(), &BlockRequest{Hash: unknown[:]}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetBlock(unknown) = %v, want %v", err, codes.NotFound)
+	}
+}
+
+func TestGetDelegatesAndRound(t *testing.T) {
+	node, _, _ := newTestNode(t)
+	client := newTestClient(t, node)
+	dpos := node.DPoS
+
+	delegates, err := client.GetDelegates(context.Background(), &DelegatesRequest{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(delegates.GetDelegates()) != len(dpos.Delegates) {
+		t.Fatalf("GetDelegates returned %v delegates, want %v", len(delegates.GetDelegates()), len(dpos.Delegates))
+	}
+
+	for i, delegate := range delegates.GetDelegates() {
+		want := dpos.Delegates[i]
+
+		if (delegate.GetIban() != want.Account.IBAN.String()) || (delegate.GetUsername() != node.Ledger.Username(want.Account.IBAN)) {
+			t.Errorf("Delegate %v = %v", i, delegate)
+		}
+
+		checkAmount(t, "Weight", delegate.GetWeight(), want.Weight)
+	}
+
+	round, err := client.GetRound(context.Background(), &RoundRequest{})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if (round.GetNumber() != dpos.Rounds) || (round.GetIndex() != uint64(dpos.Round.Index)) || (len(round.GetForgers()) != len(dpos.Round.Forgers)) {
+		t.Errorf("GetRound = %v", round)
+	}
+
+	for i, forger := range round.GetForgers() {
+		if forger.GetIban() != dpos.Round.Forgers[i].Account.IBAN.String() {
+			t.Errorf("Forger %v = %v", i, forger.GetIban())
+		}
+	}
+}
+
+func TestSubscribeBlocks(t *testing.T) {
+	node, genesis, account := newTestNode(t)
+	client := newTestClient(t, node)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stream, err := client.SubscribeBlocks(ctx, &SubscribeRequest{Ibans: []string{account.IBAN.String()}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Headers arrive once the subscription is active.
+	if _, err := stream.Header(); err != nil {
+		t.Fatal(err)
+	}
+
+	pay(t, node, 2, account, genesis)
+	event, err := stream.Recv()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Only blocks appended to the account are streamed so the SendBlock of genesis is skipped.
+	if (event.GetIban() != account.IBAN.String()) || (event.GetAmount() != "2") || (event.GetBlock().GetReceive() == nil) {
+		t.Errorf("Recv = %v, want ReceiveBlock of 2", event)
+	}
+
+	latest := node.Ledger.LatestBlock(account.IBAN)
+	want, _ := latest.Hash()
+
+	if got, err := decodeBlock(t, event.GetBlock()).Hash(); (err != nil) || (got != want) {
+		t.Errorf("Hash of streamed block = %v, %v, want %v", got, err, want)
+	}
+
+	cancel()
+
+	if _, err := stream.Recv(); status.Code(err) != codes.Canceled {
+		t.Errorf("Recv after cancel = %v, want %v", err, codes.Canceled)
+	}
+
+	invalid, err := client.SubscribeBlocks(context.Background(), &SubscribeRequest{Ibans: []string{"TV00"}})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := invalid.Recv(); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("SubscribeBlocks(invalid IBAN) = %v, want %v", err, codes.InvalidArgument)
+	}
+}
+
+// TestConcurrentProcess calls the service while requests are processed.
+// Run with -race to detect unsynchronized reads of the Ledger and DPoS.
+func TestConcurrentProcess(t *testing.T) {
+	node, genesis, account := newTestNode(t)
+	client := newTestClient(t, node)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+
+	calls := []func() error{
+		func() error {
+			_, err := client.GetAccount(context.Background(), &AccountRequest{Id: "merchant"})
+			return err
+		},
+		func() error {
+			_, err := client.GetDelegates(context.Background(), &DelegatesRequest{})
+			return err
+		},
+		func() error {
+			_, err := client.GetRound(context.Background(), &RoundRequest{})
+			return err
+		},
+	}
+
+	for _, call := range calls {
+		wg.Add(1)
+
+		go func(call func() error) {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if err := call(); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}(call)
+	}
+
+	for i := 0; i < 20; i++ {
+		pay(t, node, 0.5, account, genesis)
+	}
+
+	close(done)
+	wg.Wait()
+}
@@ -0,0 +1,358 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kookehs/watchmen/core"
+	"github.com/kookehs/watchmen/primitives"
+)
+
+// Defines retries and limits of deliveries
+var (
+	// Retries
+	InitialBackoff time.Duration = time.Second
+	MaxAttempts    int           = 5
+	MaxBackoff     time.Duration = time.Minute
+
+	// Limits
+	EventBufferSize int           = 1024
+	MaxDeliveryLogs int           = 100
+	Timeout         time.Duration = 10 * time.Second
+	Workers         int           = 4
+)
+
+// Attempt records the outcome of a single POST of a Delivery.
+type Attempt struct {
+	Duration   time.Duration `json:"duration"`
+	Error      string        `json:"error,omitempty"`
+	StatusCode int           `json:"status"`
+	Time       int64         `json:"time"`
+}
+
+// Delivery records every attempt to deliver a Notification to a Webhook.
+type Delivery struct {
+	Attempts     []Attempt    `json:"attempts"`
+	Delivered    bool         `json:"delivered"`
+	ID           uuid.UUID    `json:"id"`
+	Notification Notification `json:"notification"`
+	WebhookID    uuid.UUID    `json:"webhook"`
+}
+
+// Dispatcher delivers signed callbacks to webhooks for payments appended by a Node.
+// Failed deliveries are retried with exponential backoff up to MaxAttempts.
+type Dispatcher struct {
+	Client *http.Client
+	Node   *core.Node
+
+	deliveries   map[uuid.UUID][]*Delivery
+	group        sync.WaitGroup
+	mutex        sync.Mutex
+	queue        chan *Delivery
+	stop         chan struct{}
+	subscription *core.Subscription
+	webhooks     map[uuid.UUID]*Webhook
+}
+
+// NewDispatcher returns a pointer to an initialized Dispatcher for the given Node.
+func NewDispatcher(node *core.Node) *Dispatcher {
+	return &Dispatcher{
+		Client:     &http.Client{Timeout: Timeout},
+		Node:       node,
+		deliveries: make(map[uuid.UUID][]*Delivery),
+		queue:      make(chan *Delivery, EventBufferSize),
+		stop:       make(chan struct{}),
+		webhooks:   make(map[uuid.UUID]*Webhook),
+	}
+}
+
+// Deliveries returns the most recent deliveries of the Webhook with the given ID from oldest to newest.
+func (d *Dispatcher) Deliveries(id uuid.UUID) []Delivery {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	deliveries := make([]Delivery, 0, len(d.deliveries[id]))
+
+	for _, delivery := range d.deliveries[id] {
+		copied := *delivery
+		copied.Attempts = append([]Attempt(nil), delivery.Attempts...)
+		deliveries = append(deliveries, copied)
+	}
+
+	return deliveries
+}
+
+// Register creates a Webhook notifying the given Account of incoming payments at the given URL.
+// The secret of the returned Webhook must be shared with the receiver to verify callbacks.
+func (d *Dispatcher) Register(account *core.Account, callback string) (*Webhook, error) {
+	if account == nil {
+		return nil, errors.New("Webhook requires an account")
+	}
+
+	webhook, err := NewWebhook(account.IBAN, callback)
+
+	if err != nil {
+		return nil, err
+	}
+
+	d.mutex.Lock()
+	d.webhooks[webhook.ID] = webhook
+	d.mutex.Unlock()
+	return webhook, nil
+}
+
+// Start subscribes to the events of the Node and starts delivering callbacks.
+func (d *Dispatcher) Start() {
+	if d.Node.Events == nil {
+		log.Println("Node does not publish events")
+		return
+	}
+
+	d.subscription = d.Node.Events.Subscribe(EventBufferSize, core.BlockAppended)
+	d.group.Add(1)
+	go d.listen()
+
+	for i := 0; i < Workers; i++ {
+		d.group.Add(1)
+		go d.work()
+	}
+}
+
+// Stop stops delivering callbacks and waits for pending attempts to finish.
+// Deliveries waiting to be retried are abandoned.
+func (d *Dispatcher) Stop() {
+	close(d.stop)
+
+	if d.subscription != nil {
+		d.subscription.Close()
+	}
+
+	d.group.Wait()
+}
+
+// Unregister removes the Webhook with the given ID along with its delivery logs.
+func (d *Dispatcher) Unregister(id uuid.UUID) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if _, exist := d.webhooks[id]; !exist {
+		return fmt.Errorf("Webhook %v does not exist", id)
+	}
+
+	delete(d.webhooks, id)
+	delete(d.deliveries, id)
+	return nil
+}
+
+// Webhooks returns the webhooks registered for the given IBAN.
+func (d *Dispatcher) Webhooks(iban primitives.IBAN) []*Webhook {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	webhooks := make([]*Webhook, 0)
+
+	for _, webhook := range d.webhooks {
+		if webhook.IBAN == iban.String() {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks
+}
+
+// attempt POSTs the Notification of the given Delivery once and records the outcome.
+func (d *Dispatcher) attempt(webhook *Webhook, delivery *Delivery, body []byte) bool {
+	start := time.Now()
+	attempt := Attempt{
+		Time: start.UnixNano(),
+	}
+
+	request, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+
+	if err == nil {
+		timestamp := start.Unix()
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set(DeliveryHeader, delivery.ID.String())
+		request.Header.Set(SignatureHeader, webhook.Sign(timestamp, body))
+		request.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+
+		var response *http.Response
+		response, err = d.Client.Do(request)
+
+		if err == nil {
+			response.Body.Close()
+			attempt.StatusCode = response.StatusCode
+
+			if (response.StatusCode < 200) || (response.StatusCode > 299) {
+				err = fmt.Errorf("Unexpected status %v", response.Status)
+			}
+		}
+	}
+
+	attempt.Duration = time.Since(start)
+
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	d.mutex.Lock()
+	delivery.Attempts = append(delivery.Attempts, attempt)
+	delivery.Delivered = (err == nil)
+	d.mutex.Unlock()
+	return err == nil
+}
+
+// backoff returns the delay before the given retry.
+func backoff(retry int) time.Duration {
+	delay := InitialBackoff
+
+	for i := 1; (i < retry) && (delay < MaxBackoff); i++ {
+		delay *= 2
+	}
+
+	if delay > MaxBackoff {
+		delay = MaxBackoff
+	}
+
+	return delay
+}
+
+// deliver attempts the given Delivery until it succeeds, runs out of attempts or the Dispatcher stops.
+func (d *Dispatcher) deliver(delivery *Delivery) {
+	d.mutex.Lock()
+	webhook, exist := d.webhooks[delivery.WebhookID]
+	d.mutex.Unlock()
+
+	if !exist {
+		return
+	}
+
+	body, err := json.Marshal(delivery.Notification)
+
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	for retry := 0; retry < MaxAttempts; retry++ {
+		if retry > 0 {
+			select {
+			case <-time.After(backoff(retry)):
+			case <-d.stop:
+				return
+			}
+		}
+
+		if d.attempt(webhook, delivery, body) {
+			return
+		}
+	}
+
+	log.Printf("Giving up on delivery %v to %v\n", delivery.ID, webhook.URL)
+}
+
+// listen queues a Delivery for every webhook of the accounts paid by appended blocks.
+func (d *Dispatcher) listen() {
+	defer d.group.Done()
+
+	for event := range d.subscription.C {
+		for _, notification := range notifications(event) {
+			d.mutex.Lock()
+			deliveries := make([]*Delivery, 0)
+
+			for _, webhook := range d.webhooks {
+				if webhook.IBAN != notification.IBAN {
+					continue
+				}
+
+				delivery := &Delivery{
+					Attempts:     make([]Attempt, 0),
+					Delivered:    false,
+					ID:           uuid.New(),
+					Notification: notification,
+					WebhookID:    webhook.ID,
+				}
+
+				logs := append(d.deliveries[webhook.ID], delivery)
+
+				if len(logs) > MaxDeliveryLogs {
+					logs = logs[len(logs)-MaxDeliveryLogs:]
+				}
+
+				d.deliveries[webhook.ID] = logs
+				deliveries = append(deliveries, delivery)
+			}
+
+			d.mutex.Unlock()
+
+			for _, delivery := range deliveries {
+				select {
+				case d.queue <- delivery:
+				case <-d.stop:
+					return
+				}
+			}
+		}
+	}
+}
+
+// work delivers queued deliveries until the Dispatcher stops.
+func (d *Dispatcher) work() {
+	defer d.group.Done()
+
+	for {
+		select {
+		case delivery := <-d.queue:
+			d.deliver(delivery)
+		case <-d.stop:
+			return
+		}
+	}
+}
+
+// notifications returns the notifications for the accounts paid by the block of the given event.
+// SendBlocks notify their destination and ReceiveBlocks notify the account receiving.
+func notifications(event core.Event) []Notification {
+	if event.Block == nil {
+		return nil
+	}
+
+	hash, err := event.Block.Hash()
+
+	if err != nil {
+		log.Println(err)
+		return nil
+	}
+
+	notification := Notification{
+		Amount:    "0",
+		Block:     event.Block,
+		Hash:      hash.String(),
+		Timestamp: event.Block.Timestamp(),
+	}
+
+	if event.Amount != nil {
+		notification.Amount = event.Amount.Text('g', -1)
+	}
+
+	switch block := event.Block.(type) {
+	case *primitives.SendBlock:
+		notification.IBAN = block.Hashables.Destination.String()
+		notification.Kind = SendNotification
+	case *primitives.ReceiveBlock:
+		notification.IBAN = event.IBAN
+		notification.Kind = ReceiveNotification
+	default:
+		return nil
+	}
+
+	return []Notification{notification}
+}
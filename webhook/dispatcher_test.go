@@ -0,0 +1,330 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kookehs/watchmen/core"
+	"github.com/kookehs/watchmen/primitives"
+)
+
+type status struct{}
+
+func (status) Available(string) bool {
+	return true
+}
+
+// callback is a request received by a test server.
+type callback struct {
+	body   []byte
+	header http.Header
+}
+
+// notice contains the fields of a Notification checked by tests.
+type notice struct {
+	Amount string `json:"amount"`
+	Hash   string `json:"hash"`
+	IBAN   string `json:"iban"`
+	Kind   string `json:"kind"`
+}
+
+// setRetries shrinks the retry settings for the duration of the test.
+func setRetries(t *testing.T, initial time.Duration, attempts int) {
+	t.Helper()
+	backoff, max := InitialBackoff, MaxAttempts
+	InitialBackoff, MaxAttempts = initial, attempts
+
+	t.Cleanup(func() {
+		InitialBackoff, MaxAttempts = backoff, max
+	})
+}
+
+// newTestNode returns a Node with a funded genesis account and an empty account to be paid.
+func newTestNode(t *testing.T) (*core.Node, *core.Account, *core.Account) {
+	t.Helper()
+	ledger := core.NewLedger()
+	dpos := core.NewDPoS()
+	node := core.NewNode(dpos, ledger, status{})
+	genesis, err := node.OpenGenesisAccount("genesis")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	node.OpenGenesisDelegates(genesis)
+	account, err := node.OpenAccount("merchant")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return node, genesis, account
+}
+
+// pay sends the given amount from src to dst.
+func pay(t *testing.T, node *core.Node, amt float64, dst, src *core.Account) primitives.Block {
+	t.Helper()
+	block, err := node.Transfer(primitives.NewAmount(amt), dst.IBAN, src.IBAN)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return block
+}
+
+// waitFor polls the given condition until it holds or the test times out.
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// sends returns the deliveries of SendNotifications of the given Webhook.
+func sends(d *Dispatcher, webhook *Webhook) []Delivery {
+	deliveries := make([]Delivery, 0)
+
+	for _, delivery := range d.Deliveries(webhook.ID) {
+		if delivery.Notification.Kind == SendNotification {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries
+}
+
+func TestDeliverySigned(t *testing.T) {
+	callbacks := make(chan callback, 16)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		callbacks <- callback{body: body, header: r.Header.Clone()}
+	}))
+	defer server.Close()
+
+	node, genesis, account := newTestNode(t)
+	dispatcher := NewDispatcher(node)
+	webhook, err := dispatcher.Register(account, server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.Start()
+	defer dispatcher.Stop()
+
+	// The amount has more significant digits than String keeps so it must not be rounded.
+	block := pay(t, node, 9.87654321012, account, genesis)
+	hash, err := block.Hash()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for {
+		var received callback
+
+		select {
+		case received = <-callbacks:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for callback")
+		}
+
+		var notification notice
+
+		if err := json.Unmarshal(received.body, &notification); err != nil {
+			t.Fatal(err)
+		}
+
+		if notification.Kind != SendNotification {
+			continue
+		}
+
+		timestamp, err := strconv.ParseInt(received.header.Get(TimestampHeader), 10, 64)
+
+		if err != nil {
+			t.Fatalf("Invalid %v header: %v", TimestampHeader, err)
+		}
+
+		signature := received.header.Get(SignatureHeader)
+
+		if !Verify(webhook.Secret, timestamp, received.body, signature, time.Minute) {
+			t.Errorf("Signature %q does not verify", signature)
+		}
+
+		if Verify(webhook.Secret, timestamp+1, received.body, signature, time.Minute) {
+			t.Error("Signature verifies with a different timestamp")
+		}
+
+		if Verify("secret", timestamp, received.body, signature, time.Minute) {
+			t.Error("Signature verifies with a different secret")
+		}
+
+		if (notification.Hash != hash.String()) || (notification.IBAN != account.IBAN.String()) || (notification.Amount != "9.87654321012") {
+			t.Errorf("Notification = %+v", notification)
+		}
+
+		waitFor(t, func() bool {
+			deliveries := sends(dispatcher, webhook)
+			return (len(deliveries) == 1) && deliveries[0].Delivered
+		})
+
+		if id := sends(dispatcher, webhook)[0].ID.String(); received.header.Get(DeliveryHeader) != id {
+			t.Errorf("%v = %q, want %q", DeliveryHeader, received.header.Get(DeliveryHeader), id)
+		}
+
+		return
+	}
+}
+
+func TestDeliveryRetry(t *testing.T) {
+	setRetries(t, 20*time.Millisecond, 5)
+	var requests atomic.Int32
+
+	// Send callbacks fail twice before succeeding.
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification notice
+
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if (notification.Kind == SendNotification) && (requests.Add(1) <= 2) {
+			http.Error(w, "Unavailable", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	node, genesis, account := newTestNode(t)
+	dispatcher := NewDispatcher(node)
+	webhook, err := dispatcher.Register(account, server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.Start()
+	defer dispatcher.Stop()
+	pay(t, node, 5, account, genesis)
+
+	waitFor(t, func() bool {
+		deliveries := sends(dispatcher, webhook)
+		return (len(deliveries) == 1) && deliveries[0].Delivered
+	})
+
+	attempts := sends(dispatcher, webhook)[0].Attempts
+
+	if len(attempts) != 3 {
+		t.Fatalf("Attempts = %+v, want 3", attempts)
+	}
+
+	for i, want := range []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK} {
+		if attempts[i].StatusCode != want {
+			t.Errorf("Attempt %v status = %v, want %v", i, attempts[i].StatusCode, want)
+		}
+
+		if (want != http.StatusOK) && (attempts[i].Error == "") {
+			t.Errorf("Attempt %v does not record an error", i)
+		}
+	}
+
+	for i := 1; i < len(attempts); i++ {
+		delay := time.Duration(attempts[i].Time - attempts[i-1].Time)
+
+		if delay < backoff(i) {
+			t.Errorf("Attempt %v followed after %v, want at least %v", i, delay, backoff(i))
+		}
+	}
+}
+
+func TestDeliveryGivesUp(t *testing.T) {
+	setRetries(t, time.Millisecond, 3)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	node, genesis, account := newTestNode(t)
+	dispatcher := NewDispatcher(node)
+	webhook, err := dispatcher.Register(account, server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.Start()
+	defer dispatcher.Stop()
+	pay(t, node, 5, account, genesis)
+
+	waitFor(t, func() bool {
+		deliveries := sends(dispatcher, webhook)
+		return (len(deliveries) == 1) && (len(deliveries[0].Attempts) == MaxAttempts)
+	})
+
+	// No attempts are made after MaxAttempts.
+	time.Sleep(20 * time.Millisecond)
+	delivery := sends(dispatcher, webhook)[0]
+
+	if delivery.Delivered || (len(delivery.Attempts) != MaxAttempts) {
+		t.Errorf("Delivery = %+v, want %v failed attempts", delivery, MaxAttempts)
+	}
+}
+
+func TestStop(t *testing.T) {
+	setRetries(t, time.Hour, 5)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Internal error", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	node, genesis, account := newTestNode(t)
+	goroutines := runtime.NumGoroutine()
+	dispatcher := NewDispatcher(node)
+	webhook, err := dispatcher.Register(account, server.URL)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher.Start()
+	pay(t, node, 5, account, genesis)
+
+	// The delivery is waiting to be retried after its first attempt.
+	waitFor(t, func() bool {
+		deliveries := sends(dispatcher, webhook)
+		return (len(deliveries) == 1) && (len(deliveries[0].Attempts) == 1)
+	})
+
+	stopped := make(chan struct{})
+
+	go func() {
+		dispatcher.Stop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop did not return while a delivery was waiting to be retried")
+	}
+
+	dispatcher.Client.CloseIdleConnections()
+
+	waitFor(t, func() bool {
+		return runtime.NumGoroutine() <= goroutines
+	})
+}
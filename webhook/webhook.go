@@ -0,0 +1,139 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kookehs/watchmen/primitives"
+)
+
+// Headers set on every callback
+const (
+	DeliveryHeader  = "X-Watchmen-Delivery"
+	SignatureHeader = "X-Watchmen-Signature"
+	TimestampHeader = "X-Watchmen-Timestamp"
+)
+
+// SecretSize is the length of the secret used to sign callbacks in bytes.
+const SecretSize = 32
+
+// Kinds of payments a Notification describes
+const (
+	// SendNotification is delivered to the destination of a SendBlock.
+	SendNotification = "send"
+	// ReceiveNotification is delivered to the account of a ReceiveBlock.
+	ReceiveNotification = "receive"
+)
+
+// Webhook is a URL registered by an account to be notified of incoming payments.
+type Webhook struct {
+	Created int64     `json:"created"`
+	IBAN    string    `json:"iban"`
+	ID      uuid.UUID `json:"id"`
+	// Secret shared with the receiver to verify the signature of callbacks
+	Secret string `json:"secret"`
+	URL    string `json:"url"`
+}
+
+// NewWebhook creates and initializes a Webhook with a random secret for the given IBAN and URL.
+func NewWebhook(iban primitives.IBAN, callback string) (*Webhook, error) {
+	parsed, err := url.Parse(callback)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if ((parsed.Scheme != "http") && (parsed.Scheme != "https")) || (parsed.Host == "") {
+		return nil, errors.New("Webhook URL must be an absolute HTTP URL")
+	}
+
+	id, err := uuid.NewRandom()
+
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, SecretSize)
+
+	if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+		return nil, err
+	}
+
+	return &Webhook{
+		Created: time.Now().UnixNano(),
+		IBAN:    iban.String(),
+		ID:      id,
+		Secret:  hex.EncodeToString(secret),
+		URL:     callback,
+	}, nil
+}
+
+// Sign returns the signature of the given body sent at the given Unix timestamp.
+func (w *Webhook) Sign(timestamp int64, body []byte) string {
+	return Sign(w.Secret, timestamp, body)
+}
+
+// Deserialize decodes byte data encoded by gob.
+func (w *Webhook) Deserialize(r io.Reader) error {
+	decoder := gob.NewDecoder(r)
+	return decoder.Decode(w)
+}
+
+// DeserializeJSON decodes JSON data.
+func (w *Webhook) DeserializeJSON(r io.Reader) error {
+	decoder := json.NewDecoder(r)
+	return decoder.Decode(w)
+}
+
+// Serialize encodes to byte data using gob.
+func (w *Webhook) Serialize(wr io.Writer) error {
+	encoder := gob.NewEncoder(wr)
+	return encoder.Encode(w)
+}
+
+// SerializeJSON encodes to JSON data.
+func (w *Webhook) SerializeJSON(wr io.Writer) error {
+	encoder := json.NewEncoder(wr)
+	return encoder.Encode(w)
+}
+
+// Notification is the JSON body of a callback.
+type Notification struct {
+	Amount    string           `json:"amount"`
+	Block     primitives.Block `json:"block"`
+	Hash      string           `json:"hash"`
+	IBAN      string           `json:"iban"`
+	Kind      string           `json:"kind"`
+	Timestamp int64            `json:"timestamp"`
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp and body using the given secret.
+// The timestamp is signed along with the body so that callbacks cannot be replayed later.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify returns whether the given signature of the body is valid and the timestamp is within tolerance of now.
+func Verify(secret string, timestamp int64, body []byte, signature string, tolerance time.Duration) bool {
+	sent := time.Unix(timestamp, 0)
+
+	if (tolerance > 0) && ((time.Since(sent) > tolerance) || (time.Until(sent) > tolerance)) {
+		return false
+	}
+
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
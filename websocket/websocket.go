@@ -0,0 +1,289 @@
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Message opcodes as defined in RFC 6455
+const (
+	ContinuationMessage byte = 0x0
+	TextMessage         byte = 0x1
+	BinaryMessage       byte = 0x2
+	CloseMessage        byte = 0x8
+	PingMessage         byte = 0x9
+	PongMessage         byte = 0xA
+)
+
+// MaxControlPayload is the maximum payload size of control frames in bytes.
+const MaxControlPayload = 125
+
+// acceptGUID is appended to the key of the client to compute Sec-WebSocket-Accept.
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// MaxMessageSize is the maximum size of a message read from a client in bytes.
+var MaxMessageSize int64 = 1 << 20
+
+// ErrClosed is returned when using a Conn that has been closed.
+var ErrClosed = errors.New("WebSocket connection closed")
+
+// Conn is the server side of a WebSocket connection.
+// Writes are safe for concurrent use while reads must happen from a single goroutine.
+type Conn struct {
+	closed bool
+	conn   net.Conn
+	mutex  sync.Mutex
+	reader *bufio.Reader
+}
+
+// AcceptKey returns the Sec-WebSocket-Accept value for the given Sec-WebSocket-Key.
+func AcceptKey(key string) string {
+	hash := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// Upgrade performs the opening handshake and takes over the connection of the given request.
+// An error response is written to the client if the request is not a valid WebSocket handshake.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	key := r.Header.Get("Sec-WebSocket-Key")
+
+	if (r.Method != http.MethodGet) || !headerContains(r.Header, "Upgrade", "websocket") ||
+		!headerContains(r.Header, "Connection", "upgrade") || (key == "") {
+		http.Error(w, "Expected WebSocket handshake", http.StatusBadRequest)
+		return nil, errors.New("Invalid WebSocket handshake")
+	}
+
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("Unsupported WebSocket version")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+
+	if !ok {
+		http.Error(w, "Connection cannot be upgraded", http.StatusInternalServerError)
+		return nil, errors.New("Response does not support hijacking")
+	}
+
+	conn, rw, err := hijacker.Hijack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + AcceptKey(key) + "\r\n\r\n"
+
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &Conn{
+		closed: false,
+		conn:   conn,
+		reader: rw.Reader,
+	}, nil
+}
+
+// Close sends a close frame and closes the underlying connection.
+func (c *Conn) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return nil
+	}
+
+	c.closed = true
+	c.writeFrame(CloseMessage, []byte{0x03, 0xE8})
+	return c.conn.Close()
+}
+
+// ReadMessage returns the opcode and payload of the next text or binary message.
+// Pings are answered and io.EOF is returned once the client closes the connection.
+func (c *Conn) ReadMessage() (byte, []byte, error) {
+	var opcode byte
+	message := make([]byte, 0)
+
+	for {
+		fin, op, payload, err := c.readFrame()
+
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case PingMessage:
+			if err := c.WriteMessage(PongMessage, payload); err != nil {
+				return 0, nil, err
+			}
+
+			continue
+		case PongMessage:
+			continue
+		case CloseMessage:
+			c.Close()
+			return 0, nil, io.EOF
+		case ContinuationMessage:
+			if opcode == 0 {
+				return 0, nil, errors.New("Unexpected continuation frame")
+			}
+		default:
+			if opcode != 0 {
+				return 0, nil, errors.New("Expected continuation frame")
+			}
+
+			opcode = op
+		}
+
+		if int64(len(message)+len(payload)) > MaxMessageSize {
+			return 0, nil, fmt.Errorf("Message exceeds %v bytes", MaxMessageSize)
+		}
+
+		message = append(message, payload...)
+
+		if fin {
+			return opcode, message, nil
+		}
+	}
+}
+
+// WriteMessage sends the given payload in a single frame with the given opcode.
+func (c *Conn) WriteMessage(opcode byte, payload []byte) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	return c.writeFrame(opcode, payload)
+}
+
+// readFrame reads a single frame unmasking the payload sent by the client.
+// Frames that violate RFC 6455 are rejected since no extensions are negotiated.
+func (c *Conn) readFrame() (bool, byte, []byte, error) {
+	header := make([]byte, 2)
+
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	if !masked {
+		return false, 0, nil, errors.New("Client frames must be masked")
+	}
+
+	if header[0]&0x70 != 0 {
+		return false, 0, nil, errors.New("Reserved bits must not be set")
+	}
+
+	switch opcode {
+	case ContinuationMessage, TextMessage, BinaryMessage:
+	case CloseMessage, PingMessage, PongMessage:
+		// Control frames may be sent between fragments so they cannot be fragmented themselves.
+		if !fin {
+			return false, 0, nil, errors.New("Control frames must not be fragmented")
+		}
+
+		if length > MaxControlPayload {
+			return false, 0, nil, fmt.Errorf("Control frame payload exceeds %v bytes", MaxControlPayload)
+		}
+	default:
+		return false, 0, nil, fmt.Errorf("Unknown opcode %#x", opcode)
+	}
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = int64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+
+		if _, err := io.ReadFull(c.reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+
+		length = int64(binary.BigEndian.Uint64(extended))
+	}
+
+	if (length < 0) || (length > MaxMessageSize) {
+		return false, 0, nil, fmt.Errorf("Frame exceeds %v bytes", MaxMessageSize)
+	}
+
+	mask := make([]byte, 4)
+
+	if _, err := io.ReadFull(c.reader, mask); err != nil {
+		return false, 0, nil, err
+	}
+
+	payload := make([]byte, length)
+
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// writeFrame writes a single unmasked frame with the FIN bit set.
+func (c *Conn) writeFrame(opcode byte, payload []byte) error {
+	header := []byte{0x80 | opcode}
+	length := len(payload)
+
+	switch {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	if _, err := c.conn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// headerContains returns whether the comma separated values of the given header contain the given token.
+func headerContains(header http.Header, name, token string) bool {
+	for _, value := range header[http.CanonicalHeaderKey(name)] {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,381 @@
+package websocket
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// frame returns a frame sent by a client with the given payload masked if masked is set.
+func frame(fin bool, opcode byte, payload []byte, masked bool) []byte {
+	header := []byte{opcode}
+
+	if fin {
+		header[0] |= 0x80
+	}
+
+	length := len(payload)
+
+	switch {
+	case length < 126:
+		header = append(header, byte(length))
+	case length <= 0xFFFF:
+		header = append(header, 126, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(length))
+	default:
+		header = append(header, 127, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(length))
+	}
+
+	if !masked {
+		return append(header, payload...)
+	}
+
+	header[1] |= 0x80
+	mask := []byte{0x12, 0x34, 0x56, 0x78}
+	header = append(header, mask...)
+
+	for i, b := range payload {
+		header = append(header, b^mask[i%4])
+	}
+
+	return header
+}
+
+// readFrames returns the result of ReadMessage for the given client frames and the bytes written back.
+// Reads that wait for frames that are never sent fail with os.ErrDeadlineExceeded.
+func readFrames(t *testing.T, frames ...[]byte) (byte, []byte, []byte, error) {
+	t.Helper()
+	server, client := net.Pipe()
+	server.SetDeadline(time.Now().Add(time.Second))
+	conn := &Conn{
+		conn:   server,
+		reader: bufio.NewReader(server),
+	}
+
+	go func() {
+		for _, f := range frames {
+			if _, err := client.Write(f); err != nil {
+				return
+			}
+		}
+	}()
+
+	responses := new(bytes.Buffer)
+	copied := make(chan struct{})
+
+	go func() {
+		defer close(copied)
+		io.Copy(responses, client)
+	}()
+
+	opcode, message, err := conn.ReadMessage()
+	server.Close()
+	<-copied
+	client.Close()
+	return opcode, message, responses.Bytes(), err
+}
+
+func TestReadMessage(t *testing.T) {
+	long := bytes.Repeat([]byte("a"), 300)
+
+	tests := []struct {
+		name      string
+		frames    [][]byte
+		opcode    byte
+		message   []byte
+		responses []byte
+	}{
+		{
+			"text",
+			[][]byte{frame(true, TextMessage, []byte("hello"), true)},
+			TextMessage, []byte("hello"), nil,
+		},
+		{
+			"extended length",
+			[][]byte{frame(true, BinaryMessage, long, true)},
+			BinaryMessage, long, nil,
+		},
+		{
+			"fragments around a ping",
+			[][]byte{
+				frame(false, TextMessage, []byte("hello "), true),
+				frame(true, PingMessage, []byte("ping"), true),
+				frame(false, ContinuationMessage, []byte("wor"), true),
+				frame(true, ContinuationMessage, []byte("ld"), true),
+			},
+			TextMessage, []byte("hello world"), frame(true, PongMessage, []byte("ping"), false),
+		},
+		{
+			"pong",
+			[][]byte{
+				frame(true, PongMessage, nil, true),
+				frame(true, TextMessage, nil, true),
+			},
+			TextMessage, []byte{}, nil,
+		},
+	}
+
+	for _, test := range tests {
+		opcode, message, responses, err := readFrames(t, test.frames...)
+
+		if err != nil {
+			t.Errorf("ReadMessage of %v: %v", test.name, err)
+			continue
+		}
+
+		if (opcode != test.opcode) || !bytes.Equal(message, test.message) {
+			t.Errorf("ReadMessage of %v = %v, %q, want %v, %q", test.name, opcode, message, test.opcode, test.message)
+		}
+
+		if !bytes.Equal(responses, test.responses) {
+			t.Errorf("Responses to %v = %x, want %x", test.name, responses, test.responses)
+		}
+	}
+}
+
+func TestReadMessageClose(t *testing.T) {
+	_, _, responses, err := readFrames(t, frame(true, CloseMessage, []byte{0x03, 0xE8}, true))
+
+	if err != io.EOF {
+		t.Errorf("ReadMessage of close frame = %v, want %v", err, io.EOF)
+	}
+
+	if want := frame(true, CloseMessage, []byte{0x03, 0xE8}, false); !bytes.Equal(responses, want) {
+		t.Errorf("Response to close frame = %x, want %x", responses, want)
+	}
+}
+
+func TestReadMessageErrors(t *testing.T) {
+	defer func(size int64) {
+		MaxMessageSize = size
+	}(MaxMessageSize)
+
+	// Control frames are limited below MaxMessageSize.
+	MaxMessageSize = 2 * MaxControlPayload
+	reserved := frame(true, TextMessage, []byte("hi"), true)
+	reserved[0] |= 0x40
+
+	tests := []struct {
+		name   string
+		frames [][]byte
+	}{
+		{"unmasked frame", [][]byte{frame(true, TextMessage, []byte("hi"), false)}},
+		{"reserved bits", [][]byte{reserved}},
+		{"unknown opcode", [][]byte{frame(true, 0x3, []byte("hi"), true)}},
+		{"fragmented ping", [][]byte{frame(false, PingMessage, []byte("hi"), true)}},
+		{"fragmented close", [][]byte{frame(false, CloseMessage, nil, true)}},
+		{"ping over 125 bytes", [][]byte{frame(true, PingMessage, make([]byte, MaxControlPayload+1), true)}},
+		{"continuation without message", [][]byte{frame(true, ContinuationMessage, []byte("hi"), true)}},
+		{
+			"message during fragments",
+			[][]byte{frame(false, TextMessage, []byte("hi"), true), frame(true, TextMessage, []byte("hi"), true)},
+		},
+		{"frame over MaxMessageSize", [][]byte{frame(true, BinaryMessage, make([]byte, MaxMessageSize+1), true)}},
+		{
+			"fragments over MaxMessageSize",
+			[][]byte{frame(false, BinaryMessage, make([]byte, MaxControlPayload), true), frame(true, ContinuationMessage, make([]byte, MaxControlPayload+1), true)},
+		},
+	}
+
+	for _, test := range tests {
+		_, _, _, err := readFrames(t, test.frames...)
+
+		if (err == nil) || (err == io.EOF) || errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("ReadMessage of %v = %v, want error", test.name, err)
+		}
+	}
+}
+
+func TestWriteMessage(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	conn := &Conn{
+		conn:   server,
+		reader: bufio.NewReader(server),
+	}
+
+	for _, length := range []int{0, 125, 126, 0xFFFF, 0x10000} {
+		payload := bytes.Repeat([]byte{0x5A}, length)
+		written := make(chan error, 1)
+
+		go func() {
+			written <- conn.WriteMessage(BinaryMessage, payload)
+		}()
+
+		want := frame(true, BinaryMessage, payload, false)
+		got := make([]byte, len(want))
+
+		if _, err := io.ReadFull(client, got); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := <-written; err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(got, want) {
+			t.Errorf("Frame of %v bytes does not match the expected header %x", length, want[:len(want)-length])
+		}
+	}
+
+	go io.Copy(io.Discard, client)
+
+	if err := conn.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn.WriteMessage(TextMessage, nil); !errors.Is(err, ErrClosed) {
+		t.Errorf("WriteMessage after Close = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestAcceptKey(t *testing.T) {
+	// Example from RFC 6455 section 1.3.
+	if key := AcceptKey("dGhlIHNhbXBsZSBub25jZQ=="); key != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("AcceptKey = %v, want s3pPLMBiTxaQ9kYGzzhZRbK+xOo=", key)
+	}
+}
+
+// newEchoServer returns a server that echoes every message received over WebSocket.
+func newEchoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+
+		if err != nil {
+			return
+		}
+
+		defer conn.Close()
+
+		for {
+			opcode, message, err := conn.ReadMessage()
+
+			if err != nil {
+				return
+			}
+
+			if err := conn.WriteMessage(opcode, message); err != nil {
+				return
+			}
+		}
+	}))
+
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestUpgrade(t *testing.T) {
+	server := newEchoServer(t)
+	conn, err := net.Dial("tcp", strings.TrimPrefix(server.URL, "http://"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+	key := "dGhlIHNhbXBsZSBub25jZQ=="
+	request := "GET / HTTP/1.1\r\n" +
+		"Host: " + conn.RemoteAddr().String() + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: keep-alive, Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n\r\n"
+
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := bufio.NewReader(conn)
+	response, err := http.ReadResponse(reader, nil)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Status = %v, want %v", response.StatusCode, http.StatusSwitchingProtocols)
+	}
+
+	if accept := response.Header.Get("Sec-WebSocket-Accept"); accept != AcceptKey(key) {
+		t.Errorf("Sec-WebSocket-Accept = %v, want %v", accept, AcceptKey(key))
+	}
+
+	if _, err := conn.Write(frame(true, TextMessage, []byte("echo"), true)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := frame(true, TextMessage, []byte("echo"), false)
+	got := make([]byte, len(want))
+
+	if _, err := io.ReadFull(reader, got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, want) {
+		t.Errorf("Echoed frame = %x, want %x", got, want)
+	}
+}
+
+func TestUpgradeRejectsInvalidHandshake(t *testing.T) {
+	server := newEchoServer(t)
+	valid := map[string]string{
+		"Upgrade":               "websocket",
+		"Connection":            "Upgrade",
+		"Sec-WebSocket-Key":     "dGhlIHNhbXBsZSBub25jZQ==",
+		"Sec-WebSocket-Version": "13",
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		header string
+		value  string
+		status int
+	}{
+		{"POST request", http.MethodPost, "", "", http.StatusBadRequest},
+		{"missing upgrade", http.MethodGet, "Upgrade", "", http.StatusBadRequest},
+		{"another protocol", http.MethodGet, "Upgrade", "h2c", http.StatusBadRequest},
+		{"missing connection", http.MethodGet, "Connection", "", http.StatusBadRequest},
+		{"missing key", http.MethodGet, "Sec-WebSocket-Key", "", http.StatusBadRequest},
+		{"old version", http.MethodGet, "Sec-WebSocket-Version", "8", http.StatusUpgradeRequired},
+	}
+
+	for _, test := range tests {
+		request, err := http.NewRequest(test.method, server.URL, nil)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for name, value := range valid {
+			request.Header.Set(name, value)
+		}
+
+		if test.header != "" {
+			request.Header.Set(test.header, test.value)
+		}
+
+		response, err := http.DefaultClient.Do(request)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		response.Body.Close()
+
+		if response.StatusCode != test.status {
+			t.Errorf("Status of %v = %v, want %v", test.name, response.StatusCode, test.status)
+		}
+	}
+}